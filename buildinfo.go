@@ -0,0 +1,89 @@
+package gitearelease
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// readBuildInfo is debug.ReadBuildInfo, replaceable in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// BuildInfo describes how the running program was built.
+type BuildInfo struct {
+	Version  string // Release version, e.g. "v1.2.3"; "" for development builds
+	Revision string // Full commit SHA, if known
+	Modified bool   // Built from a working tree with uncommitted changes
+	Time     string // Commit time (vcs.time), RFC3339
+}
+
+// ReadOwnBuild returns the build information of the running program. version
+// and commit are the values of the caller's -ldflags "-X main.version=... -X
+// main.commit=..." variables, if it has them; they take precedence over the main
+// module version and vcs.revision recorded by the Go toolchain. Pass "" for
+// either to rely on the toolchain alone.
+func ReadOwnBuild(version, commit string) BuildInfo {
+	var b BuildInfo
+	if info, ok := readBuildInfo(); ok {
+		b.Version = info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				b.Revision = setting.Value
+			case "vcs.modified":
+				b.Modified = setting.Value == "true"
+			case "vcs.time":
+				b.Time = setting.Value
+			}
+		}
+	}
+
+	// Go 1.24+ stamps modified builds as "v1.2.3+dirty"; Modified already says so
+	if strings.HasSuffix(b.Version, "+dirty") {
+		b.Version = strings.TrimSuffix(b.Version, "+dirty")
+		b.Modified = true
+	}
+	if b.Version == "(devel)" {
+		b.Version = ""
+	}
+	if version != "" {
+		b.Version = version
+	}
+	if commit != "" {
+		b.Revision = commit
+	}
+	return b
+}
+
+// Own returns the version in the form CompareVersions understands: the version
+// followed by the abbreviated commit, e.g. "v1.2.3-c350f37". Development builds
+// without a version are reported as "0.0.0", so any release compares newer.
+func (b BuildInfo) Own() string {
+	version := b.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+	if b.Revision != "" && extractCommitHash(version) == "" {
+		short := b.Revision
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		version += "-" + short
+	}
+	return version
+}
+
+// VersionStrings returns a VersionStrings with Own, OwnCommit and OwnModified set
+// from b; fill in Latest (and LatestCommit) before comparing.
+func (b BuildInfo) VersionStrings() VersionStrings {
+	return VersionStrings{
+		Own:         b.Own(),
+		OwnCommit:   b.Revision,
+		OwnModified: b.Modified,
+	}
+}
+
+// buildRevision returns the vcs.revision and vcs.modified build settings of the running binary.
+func buildRevision() (string, bool) {
+	b := ReadOwnBuild("", "")
+	return b.Revision, b.Modified
+}
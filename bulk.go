@@ -0,0 +1,131 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// GetRepositoriesWithLatestRelease returns the repositories selected by r, each
+// paired with its latest release. With UseGraphQL on GitHub this is a single
+// paged query; otherwise the repositories are listed and their latest releases
+// fetched over REST, Concurrency at a time (default 8).
+func GetRepositoriesWithLatestRelease(r RepositoriesToFetch) ([]RepositoryRelease, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	if gh, ok := provider.(*providers.GitHubProvider); ok && r.UseGraphQL {
+		pairs, err := fetchGitHubGraphQLRepositories(gh, baseURL, r)
+		if err != nil {
+			return nil, err
+		}
+		if !r.WithReleases {
+			return pairs, nil
+		}
+		withReleases := make([]RepositoryRelease, 0, len(pairs))
+		for _, pair := range pairs {
+			if pair.Repository.ReleaseCounter > 0 {
+				withReleases = append(withReleases, pair)
+			}
+		}
+		return withReleases, nil
+	}
+
+	repos, err := GetRepositories(r)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultEnrichConcurrency
+	}
+
+	pairs := make([]RepositoryRelease, len(repos))
+	errs := make([]error, len(repos))
+	forEachLimit(len(repos), concurrency, func(i int) {
+		pairs[i].Repository = repos[i]
+		owner, name := repoOwnerAndName(repos[i])
+		releases, err := GetReleases(ReleaseToFetch{
+			BaseURL:  r.BaseURL,
+			User:     owner,
+			Repo:     name,
+			Latest:   true,
+			Provider: string(providerType),
+			Token:    r.Token,
+		})
+		if err != nil {
+			if isStatus(err, http.StatusNotFound) {
+				return // No releases
+			}
+			errs[i] = fmt.Errorf("latest release of %q: %w", repos[i].FullName, err)
+			return
+		}
+		if len(releases) > 0 {
+			pairs[i].Latest = &releases[0]
+		}
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// fetchGitHubGraphQLRepositories pages through the repositories selected by r with the GraphQL API.
+// Users and organizations are both resolved by login; OwnerAuthenticated uses the viewer.
+func fetchGitHubGraphQLRepositories(gh *providers.GitHubProvider, baseURL string, r RepositoriesToFetch) ([]RepositoryRelease, error) {
+	apiURL := gh.GetGraphQLURL(baseURL)
+	headers := requestHeaders(gh, r.Token)
+
+	login := r.User
+	if r.Group != "" {
+		login = r.Group
+	}
+	if r.Owner == OwnerAuthenticated {
+		login = ""
+	}
+
+	var pairs []RepositoryRelease
+	cursor := ""
+	for {
+		data, err := sendJSON(http.MethodPost, apiURL, headers, gh.RepositoriesGraphQLRequest(login, cursor))
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := gh.NormalizeGraphQLRepositories(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, pr := range page.Repositories {
+			pair := RepositoryRelease{Repository: convertProviderRepository(pr)}
+			if !matchesRepositoryFilters(pair.Repository, r) {
+				continue
+			}
+			if page.Latest[i] != nil {
+				rel := convertProviderRelease(*page.Latest[i])
+				pair.Latest = &rel
+			}
+			pairs = append(pairs, pair)
+		}
+
+		if !page.HasNextPage || page.EndCursor == "" {
+			return pairs, nil
+		}
+		cursor = page.EndCursor
+	}
+}
+
+// repositoriesOf returns the repositories of pairs.
+func repositoriesOf(pairs []RepositoryRelease) []Repository {
+	repos := make([]Repository, len(pairs))
+	for i, pair := range pairs {
+		repos[i] = pair.Repository
+	}
+	return repos
+}
@@ -0,0 +1,46 @@
+package gitearelease
+
+import "github.com/earentir/gitearelease/providers"
+
+// Capabilities is a set of things a provider reports. Release.Capabilities,
+// Repository.Capabilities and Asset.Known tell which fields of a result were
+// reported, so a zero DownloadCount without CapabilityAssetDownloads means
+// "unknown" rather than "never downloaded".
+type Capabilities = providers.Capabilities
+
+// Capabilities a provider may report.
+const (
+	CapabilityLatestRelease    = providers.CapLatestRelease      // Native latest-release endpoint
+	CapabilityReleaseCount     = providers.CapReleaseCount       // Accurate repository release counts
+	CapabilityDrafts           = providers.CapDrafts             // Draft releases
+	CapabilityPrereleases      = providers.CapPrereleases        // Prerelease flag set by the publisher, not inferred from the tag
+	CapabilityTargetCommitish  = providers.CapTargetCommitish    // Branch or SHA a release was created from
+	CapabilityReleaseCommitSHA = providers.CapReleaseCommitSHA   // Tagged commit SHA in release listings
+	CapabilityAssetID          = providers.CapAssetID            // Asset IDs usable with DeleteAsset
+	CapabilityAssetSize        = providers.CapAssetSize          // Asset sizes
+	CapabilityAssetDownloads   = providers.CapAssetDownloadCount // Asset download counts
+	CapabilityAssetCreatedAt   = providers.CapAssetCreatedAt     // Asset upload times
+	CapabilityAssetContentType = providers.CapAssetContentType   // Asset MIME types
+	CapabilityAssetUUID        = providers.CapAssetUUID          // Asset UUIDs
+)
+
+// ProviderCapabilities returns what provider reports without extra requests.
+// An empty provider is detected from baseURL, as in ReleaseToFetch.
+func ProviderCapabilities(provider, baseURL string) Capabilities {
+	providerType := resolveProviderType(provider, baseURL)
+	return providerCapabilities(providers.GetProvider(providerType, baseURL))
+}
+
+// providerCapabilities returns what provider reports, or none when it does not say.
+func providerCapabilities(provider providers.Provider) Capabilities {
+	if reporter, ok := provider.(providers.CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return 0
+}
+
+// ParseCapabilities returns the capabilities named in names, such as the
+// Capabilities of a Document; unknown names are ignored.
+func ParseCapabilities(names []string) Capabilities {
+	return providers.ParseCapabilities(names)
+}
@@ -0,0 +1,90 @@
+package gitearelease
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+func TestProviders_OptionalInterfaces(t *testing.T) {
+	for _, p := range []providers.Provider{providers.NewGiteaProvider(), providers.NewGitHubProvider(), providers.NewGitLabProvider()} {
+		if _, ok := p.(providers.ReleaseFinder); !ok {
+			t.Errorf("Expected %T to implement ReleaseFinder", p)
+		}
+		if _, ok := p.(providers.TagLister); !ok {
+			t.Errorf("Expected %T to implement TagLister", p)
+		}
+		if _, ok := p.(providers.OwnerLister); !ok {
+			t.Errorf("Expected %T to implement OwnerLister", p)
+		}
+		if _, ok := p.(providers.RepositorySearcher); !ok {
+			t.Errorf("Expected %T to implement RepositorySearcher", p)
+		}
+		if _, ok := p.(providers.Authenticator); !ok {
+			t.Errorf("Expected %T to implement Authenticator", p)
+		}
+		if _, ok := p.(providers.CapabilityReporter); !ok {
+			t.Errorf("Expected %T to implement CapabilityReporter", p)
+		}
+		if _, ok := p.(providers.AssetWriter); !ok {
+			t.Errorf("Expected %T to implement AssetWriter", p)
+		}
+		if _, ok := p.(providers.WebhookParser); !ok {
+			t.Errorf("Expected %T to implement WebhookParser", p)
+		}
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	gitlab := ProviderCapabilities("", "https://gitlab.com")
+	if !gitlab.Has(CapabilityLatestRelease|CapabilityReleaseCommitSHA) || gitlab.Has(CapabilityAssetDownloads) || gitlab.Has(CapabilityDrafts) {
+		t.Errorf("Expected GitLab capabilities without downloads or drafts, got %v", gitlab)
+	}
+	if got := ProviderCapabilities("gitea", "https://gitea.example.com"); !got.Has(CapabilityReleaseCount | CapabilityAssetUUID) {
+		t.Errorf("Expected Gitea to report release counts and UUIDs, got %v", got)
+	}
+
+	names := []string{"latest_release_endpoint", "asset_size", "unknown"}
+	if got := ParseCapabilities(names); got != CapabilityLatestRelease|CapabilityAssetSize {
+		t.Errorf("Expected latest_release_endpoint,asset_size, got %v", got)
+	}
+	if got := (CapabilityAssetSize | CapabilityDrafts).Names(); !reflect.DeepEqual(got, []string{"drafts", "asset_size"}) {
+		t.Errorf("Expected names in bit order, got %v", got)
+	}
+}
+
+func TestGetReleases_ZeroVersusUnknownDownloads(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "tag_name": "v1.0.0", "assets": [{"id": 7, "name": "app.tar.gz", "size": 10, "download_count": 0}]}]`))
+	}))
+	defer github.Close()
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"tag_name": "v1.0.0", "assets": {"links": [{"id": 7, "name": "app.tar.gz", "url": "https://example.com/app.tar.gz"}], "sources": []}}]`))
+	}))
+	defer gitlab.Close()
+
+	ghReleases, err := GetReleases(ReleaseToFetch{BaseURL: github.URL, User: "o", Repo: "app", Provider: "github"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asset := ghReleases[0].Assets[0]; !asset.Known.Has(CapabilityAssetDownloads) || asset.DownloadCount != 0 {
+		t.Errorf("Expected a known zero download count on GitHub, got %d (%v)", asset.DownloadCount, asset.Known)
+	}
+	if !ghReleases[0].Capabilities.Has(CapabilityDrafts) {
+		t.Errorf("Expected GitHub releases to report drafts, got %v", ghReleases[0].Capabilities)
+	}
+
+	glReleases, err := GetReleases(ReleaseToFetch{BaseURL: gitlab.URL, User: "o", Repo: "app", Provider: "gitlab"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asset := glReleases[0].Assets[0]; asset.Known.Has(CapabilityAssetDownloads) || !asset.Known.Has(CapabilityAssetID) {
+		t.Errorf("Expected an unknown download count on GitLab, got %v", asset.Known)
+	}
+	if glReleases[0].Capabilities.Has(CapabilityDrafts) {
+		t.Errorf("Expected GitLab releases not to report drafts, got %v", glReleases[0].Capabilities)
+	}
+}
@@ -0,0 +1,294 @@
+package gitearelease
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Section kinds recognised from release note headings.
+const (
+	SectionBreaking = "breaking"
+	SectionFeatures = "features"
+	SectionFixes    = "fixes"
+	SectionSecurity = "security"
+	SectionOther    = "other"
+)
+
+// Changelog is the release notes of every release between two versions, newest first.
+type Changelog struct {
+	From    string
+	To      string
+	Entries []ChangelogEntry
+}
+
+// ChangelogEntry is one release's notes. Body is the release Body (GitLab's
+// description) verbatim; Sections is Body split at its headings.
+type ChangelogEntry struct {
+	Release  Release
+	Sections []ChangelogSection
+}
+
+// ChangelogSection is a heading of a release body and the items under it.
+// Kind is one of the Section constants; Title is the heading as written and is
+// empty for text before the first heading.
+type ChangelogSection struct {
+	Title string
+	Kind  string
+	Items []string
+}
+
+// GetChangelog collects the notes of every published release after fromVersion
+// up to and including toVersion; an empty toVersion means the newest release.
+// Drafts and tags that are not versions are skipped.
+func GetChangelog(r ReleaseToFetch, fromVersion, toVersion string) (Changelog, error) {
+	releases, err := fetchAllReleases(r)
+	if err != nil {
+		return Changelog{}, err
+	}
+	return buildChangelog(releases, fromVersion, toVersion), nil
+}
+
+// buildChangelog selects the releases in (fromVersion, toVersion] and sorts them newest first.
+func buildChangelog(releases []Release, fromVersion, toVersion string) Changelog {
+	c := Changelog{From: fromVersion, To: toVersion}
+	for _, rel := range releases {
+		if rel.Draft || !isVersion(rel.TagName) {
+			continue
+		}
+		if fromVersion != "" && compareTags(rel.TagName, fromVersion) <= 0 {
+			continue
+		}
+		if toVersion != "" && compareTags(rel.TagName, toVersion) > 0 {
+			continue
+		}
+		c.Entries = append(c.Entries, ChangelogEntry{Release: rel, Sections: parseSections(rel.Body)})
+	}
+
+	// Insertion sort keeps equal versions in provider order
+	for i := 1; i < len(c.Entries); i++ {
+		for j := i; j > 0 && compareTags(c.Entries[j].Release.TagName, c.Entries[j-1].Release.TagName) > 0; j-- {
+			c.Entries[j], c.Entries[j-1] = c.Entries[j-1], c.Entries[j]
+		}
+	}
+	return c
+}
+
+// DedupePrereleases drops prereleases whose final version is also in the
+// changelog, since their notes are usually repeated in the final release.
+func (c Changelog) DedupePrereleases() Changelog {
+	final := map[string]bool{}
+	for _, e := range c.Entries {
+		if isStableVersion(e.Release.TagName) {
+			final[prereleaseBase(e.Release.TagName)] = true
+		}
+	}
+
+	deduped := Changelog{From: c.From, To: c.To}
+	for _, e := range c.Entries {
+		if !isStableVersion(e.Release.TagName) && final[prereleaseBase(e.Release.TagName)] {
+			continue
+		}
+		deduped.Entries = append(deduped.Entries, e)
+	}
+	return deduped
+}
+
+// Markdown renders the changelog with one "## tag" heading per release.
+func (c Changelog) Markdown() string {
+	var b strings.Builder
+	for i, e := range c.Entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n", entryTitle(e.Release))
+		if body := strings.TrimSpace(e.Release.Body); body != "" {
+			b.WriteString("\n" + body + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Text renders the changelog as plain text with Markdown syntax stripped.
+func (c Changelog) Text() string {
+	var b strings.Builder
+	for i, e := range c.Entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := entryTitle(e.Release)
+		fmt.Fprintf(&b, "%s\n%s\n", title, strings.Repeat("=", len(title)))
+		for _, s := range e.Sections {
+			if s.Title != "" {
+				fmt.Fprintf(&b, "\n%s:\n", stripMarkdown(s.Title))
+			}
+			for _, item := range s.Items {
+				fmt.Fprintf(&b, "  - %s\n", stripMarkdown(item))
+			}
+		}
+	}
+	return b.String()
+}
+
+// Sections merges the sections of every release by kind, in the order
+// breaking, features, fixes, security, other. Items keep release order.
+func (c Changelog) Sections() []ChangelogSection {
+	kinds := []string{SectionBreaking, SectionFeatures, SectionFixes, SectionSecurity, SectionOther}
+	merged := make(map[string]*ChangelogSection, len(kinds))
+	for _, e := range c.Entries {
+		for _, s := range e.Sections {
+			m, ok := merged[s.Kind]
+			if !ok {
+				m = &ChangelogSection{Title: sectionTitles[s.Kind], Kind: s.Kind}
+				merged[s.Kind] = m
+			}
+			m.Items = append(m.Items, s.Items...)
+		}
+	}
+
+	var sections []ChangelogSection
+	for _, kind := range kinds {
+		if m, ok := merged[kind]; ok && len(m.Items) > 0 {
+			sections = append(sections, *m)
+		}
+	}
+	return sections
+}
+
+var sectionTitles = map[string]string{
+	SectionBreaking: "Breaking Changes",
+	SectionFeatures: "Features",
+	SectionFixes:    "Fixes",
+	SectionSecurity: "Security",
+	SectionOther:    "Other",
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	bulletPattern   = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+	breakingPattern = regexp.MustCompile(`^\s*BREAKING[ -]CHANGES?:\s*(.*)$`)
+)
+
+// parseSections splits a Markdown release body at its headings. Bullets and
+// paragraphs become items; Conventional Commits "BREAKING CHANGE:" lines are
+// collected into a breaking section wherever they appear.
+func parseSections(body string) []ChangelogSection {
+	var sections []ChangelogSection
+	var breaking []string
+	current := &ChangelogSection{Kind: SectionOther}
+	paragraph := false
+
+	flush := func() {
+		if current.Title != "" || len(current.Items) > 0 {
+			sections = append(sections, *current)
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			current = &ChangelogSection{Title: m[1], Kind: sectionKind(m[1])}
+			paragraph = false
+			continue
+		}
+		if m := breakingPattern.FindStringSubmatch(line); m != nil {
+			breaking = append(breaking, m[1])
+			paragraph = false
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch m := bulletPattern.FindStringSubmatch(line); {
+		case trimmed == "":
+			paragraph = false
+		case m != nil:
+			current.Items = append(current.Items, m[1])
+			paragraph = true
+		case paragraph && len(current.Items) > 0:
+			// Continuation of the previous bullet or paragraph
+			current.Items[len(current.Items)-1] += " " + trimmed
+		default:
+			current.Items = append(current.Items, trimmed)
+			paragraph = true
+		}
+	}
+	flush()
+
+	if len(breaking) > 0 {
+		sections = append([]ChangelogSection{{Title: sectionTitles[SectionBreaking], Kind: SectionBreaking, Items: breaking}}, sections...)
+	}
+	return sections
+}
+
+// sectionKind classifies a heading such as "Bug Fixes" or "⚠ BREAKING CHANGES".
+func sectionKind(title string) string {
+	t := strings.ToLower(title)
+	switch {
+	case strings.Contains(t, "breaking"):
+		return SectionBreaking
+	case strings.Contains(t, "secur"):
+		return SectionSecurity
+	case strings.Contains(t, "fix") || strings.Contains(t, "bug"):
+		return SectionFixes
+	case strings.Contains(t, "feat") || strings.Contains(t, "new") || strings.Contains(t, "add"):
+		return SectionFeatures
+	}
+	return SectionOther
+}
+
+var (
+	linkPattern     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasisPattern = regexp.MustCompile("(\\*\\*|__|\\*|_|~~|`)([^*_~`]+)(\\*\\*|__|\\*|_|~~|`)")
+)
+
+// stripMarkdown removes inline Markdown: links keep their text, emphasis and code spans their content.
+func stripMarkdown(s string) string {
+	s = linkPattern.ReplaceAllString(s, "$1")
+	return emphasisPattern.ReplaceAllString(s, "$2")
+}
+
+// entryTitle returns the tag, followed by the release name and date when they add information.
+func entryTitle(rel Release) string {
+	title := rel.TagName
+	if rel.Name != "" && rel.Name != rel.TagName {
+		title += " - " + rel.Name
+	}
+	if date := rel.ReleaseTime(); !date.IsZero() {
+		title += " (" + date.Format("2006-01-02") + ")"
+	}
+	return title
+}
+
+// isVersion reports whether tag starts like a version number once its prefix is removed.
+func isVersion(tag string) bool {
+	core := versionCore(tag)
+	return core != "" && core[0] >= '0' && core[0] <= '9'
+}
+
+// compareTags compares two version tags, ignoring build commit suffixes.
+// Unlike CompareVersions, a prerelease sorts before its final version.
+func compareTags(a, b string) int {
+	baseA, baseB := prereleaseBase(a), prereleaseBase(b)
+	if c := CompareVersions(VersionStrings{Own: baseA, Latest: baseB}); c != 0 {
+		return c
+	}
+	preA, preB := versionCore(a) != baseA, versionCore(b) != baseB
+	switch {
+	case preA && !preB:
+		return -1
+	case !preA && preB:
+		return 1
+	case preA && preB:
+		return CompareVersions(VersionStrings{Own: versionCore(a), Latest: versionCore(b)})
+	}
+	return 0
+}
+
+// prereleaseBase returns the version of tag without its prerelease suffix: "v2.0.0-rc1" becomes "2.0.0".
+func prereleaseBase(tag string) string {
+	core := versionCore(tag)
+	if idx := strings.IndexAny(core, "-+"); idx >= 0 {
+		return core[:idx]
+	}
+	return core
+}
@@ -0,0 +1,96 @@
+package gitearelease
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetChangelog(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/testuser/testrepo/releases" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[
+			{"id": 6, "tag_name": "v1.3.0", "draft": true, "body": "unreleased"},
+			{"id": 4, "tag_name": "v1.2.0-rc1", "published_at": "2024-02-20T00:00:00Z", "body": "## Features\n- **Parallel** downloads"},
+			{"id": 5, "tag_name": "v1.2.0", "published_at": "2024-03-01T00:00:00Z", "body": "## Features\n\n- **Parallel** downloads\n  with retries\n\n## Bug Fixes\n\n- Fix [timeout](https://example.com/1)\n\nBREAKING CHANGE: drop Go 1.20"},
+			{"id": 3, "tag_name": "nightly", "body": "nightly build"},
+			{"id": 2, "tag_name": "v1.1.0", "published_at": "2024-01-15T00:00:00Z", "body": "Small release.\r\n\r\n### Security\r\n* Bump ` + "`x/net`" + `"},
+			{"id": 1, "tag_name": "v1.0.0", "published_at": "2024-01-01T00:00:00Z", "body": "Initial release"}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL, User: "testuser", Repo: "testrepo", Provider: "github"}
+	changelog, err := GetChangelog(r, "v1.0.0", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var tags []string
+	for _, e := range changelog.Entries {
+		tags = append(tags, e.Release.TagName)
+	}
+	if strings.Join(tags, " ") != "v1.2.0 v1.2.0-rc1 v1.1.0" {
+		t.Fatalf("Expected v1.2.0 v1.2.0-rc1 v1.1.0, got %v", tags)
+	}
+
+	changelog = changelog.DedupePrereleases()
+	if len(changelog.Entries) != 2 {
+		t.Fatalf("Expected the release candidate to be deduped, got %d entries", len(changelog.Entries))
+	}
+
+	md := changelog.Markdown()
+	if !strings.HasPrefix(md, "## v1.2.0 (2024-03-01)\n\n## Features\n\n- **Parallel** downloads\n  with retries\n") {
+		t.Errorf("Expected release body to be kept verbatim, got:\n%s", md)
+	}
+
+	sections := changelog.Sections()
+	expected := map[string]string{
+		SectionBreaking: "drop Go 1.20",
+		SectionFeatures: "**Parallel** downloads with retries",
+		SectionFixes:    "Fix [timeout](https://example.com/1)",
+		SectionSecurity: "Bump `x/net`",
+		SectionOther:    "Small release.",
+	}
+	if len(sections) != len(expected) {
+		t.Fatalf("Expected %d sections, got %+v", len(expected), sections)
+	}
+	for _, s := range sections {
+		if len(s.Items) != 1 || s.Items[0] != expected[s.Kind] {
+			t.Errorf("Section %s: expected [%s], got %q", s.Kind, expected[s.Kind], s.Items)
+		}
+	}
+
+	text := changelog.Text()
+	for _, want := range []string{"v1.2.0 (2024-03-01)\n===", "Bug Fixes:\n  - Fix timeout\n", "  - Parallel downloads with retries\n", "  - Bump x/net\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected plain text to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestGetChangelog_GitLabRange(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"tag_name": "v3.0.0", "description": "## Breaking changes\n- New config format"},
+			{"tag_name": "v2.1.0", "description": "### New\n- Tags API"},
+			{"tag_name": "v2.0.0", "description": "- Baseline"}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL + "/api/v4", Project: "group/project", Provider: "gitlab"}
+	changelog, err := GetChangelog(r, "2.0.0", "v2.1.0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(changelog.Entries) != 1 || changelog.Entries[0].Release.TagName != "v2.1.0" {
+		t.Fatalf("Expected only v2.1.0, got %+v", changelog.Entries)
+	}
+	if s := changelog.Entries[0].Sections; len(s) != 1 || s[0].Kind != SectionFeatures || s[0].Items[0] != "Tags API" {
+		t.Errorf("Expected the GitLab description to be parsed, got %+v", s)
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/earentir/gitearelease"
+)
+
+func runReleases(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("releases", stderr)
+	cfg.register(fs)
+	limit := fs.Int("limit", 0, "show at most this many releases (0 for all)")
+	stable := fs.Bool("stable", false, "skip drafts and prereleases")
+	pos, code := parseFlags(fs, args, 1)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+	r, err := cfg.release(pos[0])
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	releases, err := gitearelease.GetReleases(r)
+	if err != nil {
+		return fail(stderr, err)
+	}
+	var shown []gitearelease.Release
+	for _, rel := range releases {
+		if *stable && (rel.Draft || rel.Prerelease) {
+			continue
+		}
+		if *limit > 0 && len(shown) == *limit {
+			break
+		}
+		shown = append(shown, rel)
+	}
+
+	return output(stdout, stderr, cfg.output, gitearelease.NewReleaseDocument(r, shown), func() [][]string {
+		rows := [][]string{{"TAG", "NAME", "PUBLISHED", "TYPE", "ASSETS"}}
+		for _, rel := range shown {
+			rows = append(rows, []string{rel.TagName, rel.Name, day(rel.PublishedAt), releaseType(rel), strconv.Itoa(len(rel.Assets))})
+		}
+		return rows
+	})
+}
+
+func runLatest(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("latest", stderr)
+	cfg.register(fs)
+	fallback := fs.Bool("fallback-to-tags", false, "use the highest semver tag when the repository has no releases")
+	pos, code := parseFlags(fs, args, 1)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+	r, err := cfg.release(pos[0])
+	if err != nil {
+		return fail(stderr, err)
+	}
+	r.FallbackToTags = *fallback
+
+	rel, err := getRelease(r, pos[0], "")
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	return output(stdout, stderr, cfg.output, gitearelease.NewReleaseDocument(r, []gitearelease.Release{rel}), func() [][]string {
+		rows := [][]string{
+			{"Tag:", rel.TagName},
+			{"Name:", rel.Name},
+			{"Published:", rel.PublishedAt},
+			{"Type:", releaseType(rel)},
+			{"URL:", rel.HTMLUrl},
+		}
+		for _, asset := range rel.Assets {
+			rows = append(rows, []string{"Asset:", fmt.Sprintf("%s (%d bytes)", asset.Name, asset.Size)})
+		}
+		return rows
+	})
+}
+
+func runRepos(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("repos", stderr)
+	cfg.register(fs)
+	org := fs.Bool("org", false, "list the repositories of an organization or GitLab group")
+	withReleases := fs.Bool("with-releases", false, "only list repositories with releases")
+	topic := fs.String("topic", "", "only list repositories with this topic")
+	noForks := fs.Bool("exclude-forks", false, "skip forks")
+	noArchived := fs.Bool("exclude-archived", false, "skip archived repositories")
+	pos, code := parseFlags(fs, args, 1)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+
+	rc := gitearelease.RepositoriesToFetch{
+		BaseURL:         cfg.baseURL,
+		Provider:        cfg.provider,
+		Token:           cfg.token,
+		User:            pos[0],
+		WithReleases:    *withReleases,
+		CountReleases:   *withReleases,
+		Topic:           *topic,
+		ExcludeForks:    *noForks,
+		ExcludeArchived: *noArchived,
+	}
+	if *org {
+		rc.Owner = gitearelease.OwnerOrg
+	}
+	repos, err := gitearelease.GetRepositories(rc)
+	if err != nil {
+		return fail(stderr, err)
+	}
+	return output(stdout, stderr, cfg.output, gitearelease.NewRepositoryDocument(rc, repos), func() [][]string {
+		rows := [][]string{{"REPOSITORY", "RELEASES", "STARS", "URL"}}
+		for _, repo := range repos {
+			rows = append(rows, []string{repo.FullName, strconv.Itoa(repo.ReleaseCounter), strconv.Itoa(repo.StarsCount), repo.HTMLURL})
+		}
+		return rows
+	})
+}
+
+// downloaded is the output of the download command.
+type downloaded struct {
+	Tag   string `json:"tag"`
+	Asset string `json:"asset"`
+	Path  string `json:"path"`
+}
+
+func runDownload(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("download", stderr)
+	cfg.register(fs)
+	tag := fs.String("tag", "", "release tag (default the latest release)")
+	pattern := fs.String("asset", "*", `asset names to download, as a path.Match pattern such as "*linux_amd64*"`)
+	dir := fs.String("dir", ".", "directory to save the assets in")
+	pos, code := parseFlags(fs, args, 1)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+	if _, err := path.Match(*pattern, ""); err != nil {
+		return fail(stderr, fmt.Errorf("--asset: %w", err))
+	}
+	r, err := cfg.release(pos[0])
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	rel, err := getRelease(r, pos[0], *tag)
+	if err != nil {
+		return fail(stderr, err)
+	}
+	files := []downloaded{}
+	for _, asset := range rel.Assets {
+		if ok, _ := path.Match(*pattern, asset.Name); !ok {
+			continue
+		}
+		p, err := gitearelease.DownloadReleaseAsset(r, asset, *dir, "")
+		if err != nil {
+			return fail(stderr, err)
+		}
+		files = append(files, downloaded{Tag: rel.TagName, Asset: asset.Name, Path: p})
+	}
+	if len(files) == 0 {
+		return fail(stderr, fmt.Errorf("release %s has no asset matching %q", rel.TagName, *pattern))
+	}
+
+	return output(stdout, stderr, cfg.output, files, func() [][]string {
+		rows := [][]string{{"ASSET", "PATH"}}
+		for _, f := range files {
+			rows = append(rows, []string{f.Asset, f.Path})
+		}
+		return rows
+	})
+}
+
+// checkResult is the output of the check command.
+type checkResult struct {
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+	Status  string `json:"status"` // "older", "equal" or "newer"
+	URL     string `json:"url,omitempty"`
+}
+
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("check", stderr)
+	cfg.register(fs)
+	fallback := fs.Bool("fallback-to-tags", false, "use the highest semver tag when the repository has no releases")
+	pos, code := parseFlags(fs, args, 2)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+	r, err := cfg.release(pos[0])
+	if err != nil {
+		return fail(stderr, err)
+	}
+	r.FallbackToTags = *fallback
+
+	rel, err := getRelease(r, pos[0], "")
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	result := checkResult{Current: pos[1], Latest: rel.TagName, URL: rel.HTMLUrl}
+	exit := exitOK
+	switch gitearelease.CompareVersions(gitearelease.VersionStrings{Own: pos[1], Latest: rel.TagName}) {
+	case -1:
+		result.Status, exit = "older", exitOlder
+	case 0:
+		result.Status = "equal"
+	case 1:
+		result.Status, exit = "newer", exitNewer
+	}
+
+	if code := output(stdout, stderr, cfg.output, result, func() [][]string {
+		switch result.Status {
+		case "older":
+			return [][]string{{fmt.Sprintf("%s is available (current %s)", result.Latest, result.Current)}}
+		case "newer":
+			return [][]string{{fmt.Sprintf("%s is newer than the latest release %s", result.Current, result.Latest)}}
+		}
+		return [][]string{{fmt.Sprintf("%s is the latest release", result.Current)}}
+	}); code != exitOK {
+		return code
+	}
+	return exit
+}
+
+// verifyResult is the output of the verify command.
+type verifyResult struct {
+	Tag           string `json:"tag"`
+	ReleaseCommit string `json:"release_commit,omitempty"`
+	Commit        string `json:"commit,omitempty"`
+	Verified      bool   `json:"verified"`
+}
+
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	fs := newFlagSet("verify", stderr)
+	cfg.register(fs)
+	tag := fs.String("tag", "", "release tag (default the latest release)")
+	sha := fs.String("commit", "", "commit to check, at least 7 characters (default the commit this binary was built from)")
+	pos, code := parseFlags(fs, args, 1)
+	if code >= 0 {
+		return code
+	}
+	if err := cfg.setup(); err != nil {
+		return fail(stderr, err)
+	}
+	r, err := cfg.release(pos[0])
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	rel, err := getRelease(r, pos[0], *tag)
+	if err != nil {
+		return fail(stderr, err)
+	}
+	result := verifyResult{Tag: rel.TagName, Commit: *sha}
+	if *sha == "" {
+		result.Commit = gitearelease.ReadOwnBuild(version, commit).Revision
+		if result.Verified, err = gitearelease.VerifyBuild(r, rel); err != nil {
+			return fail(stderr, err)
+		}
+	}
+	if result.ReleaseCommit, err = gitearelease.ResolveReleaseCommit(r, rel); err != nil {
+		return fail(stderr, err)
+	}
+	if *sha != "" {
+		result.Verified = len(*sha) >= 7 && strings.HasPrefix(strings.ToLower(result.ReleaseCommit), strings.ToLower(*sha))
+	}
+
+	if code := output(stdout, stderr, cfg.output, result, func() [][]string {
+		if result.Verified {
+			return [][]string{{fmt.Sprintf("%s was built from %s: verified", result.Tag, result.ReleaseCommit)}}
+		}
+		return [][]string{{fmt.Sprintf("%s was built from %s, not %s", result.Tag, result.ReleaseCommit, orNone(result.Commit))}}
+	}); code != exitOK {
+		return code
+	}
+	if !result.Verified {
+		return exitUnverified
+	}
+	return exitOK
+}
+
+func runVersion(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("version", stderr)
+	if _, code := parseFlags(fs, args, 0); code >= 0 {
+		return code
+	}
+	build := gitearelease.ReadOwnBuild(version, commit)
+	fmt.Fprintln(stdout, "gitearelease", build.Own())
+	return exitOK
+}
+
+// getRelease returns the release of tag, or the latest release when tag is empty.
+// repo is the repository as given on the command line, for error messages.
+func getRelease(r gitearelease.ReleaseToFetch, repo, tag string) (gitearelease.Release, error) {
+	if tag != "" {
+		return gitearelease.GetReleaseByTag(r, tag)
+	}
+	r.Latest = true
+	releases, err := gitearelease.GetReleases(r)
+	if err != nil {
+		return gitearelease.Release{}, err
+	}
+	if len(releases) == 0 {
+		return gitearelease.Release{}, fmt.Errorf("%s has no releases", repo)
+	}
+	return releases[0], nil
+}
+
+// output writes v in format and returns the exit code.
+func output(stdout, stderr io.Writer, format string, v interface{}, table func() [][]string) int {
+	if err := write(stdout, format, v, table); err != nil {
+		return fail(stderr, err)
+	}
+	return exitOK
+}
+
+func releaseType(rel gitearelease.Release) string {
+	switch {
+	case rel.Draft:
+		return "draft"
+	case rel.Prerelease:
+		return "prerelease"
+	}
+	return "release"
+}
+
+// day returns the date part of an RFC 3339 timestamp.
+func day(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(unknown)"
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/earentir/gitearelease"
+)
+
+// config holds the flags shared by the commands that talk to a forge.
+type config struct {
+	provider string
+	baseURL  string
+	token    string
+	output   string
+	timeout  time.Duration
+}
+
+// register adds the shared flags to fs, defaulting them from the environment.
+func (c *config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.provider, "provider", os.Getenv("GITEARELEASE_PROVIDER"), `provider: "gitea", "github", "ghes" or "gitlab"; detected from --base-url when empty`)
+	fs.StringVar(&c.baseURL, "base-url", envOr("GITEARELEASE_BASE_URL", "https://github.com"), "base URL of the forge")
+	fs.StringVar(&c.token, "token", "", "API token (default $GITEARELEASE_TOKEN, then $GITEA_TOKEN, $GITHUB_TOKEN or $GITLAB_TOKEN)")
+	fs.StringVar(&c.output, "output", envOr("GITEARELEASE_OUTPUT", "table"), `output format: "table", "json" or "yaml"`)
+	fs.DurationVar(&c.timeout, "timeout", 0, "HTTP timeout (default 15s)")
+}
+
+// setup validates the shared flags and applies the timeout.
+func (c *config) setup() error {
+	switch c.output {
+	case "table", "json", "yaml":
+	default:
+		return fmt.Errorf("unknown output format %q", c.output)
+	}
+	if c.token == "" {
+		c.token = envToken(c.provider, c.baseURL)
+	}
+	gitearelease.SetHTTPTimeout(c.timeout)
+	return nil
+}
+
+// release returns the ReleaseToFetch for an "owner/repo" argument. GitLab
+// accepts nested "group/subgroup/project" paths and numeric project IDs.
+func (c *config) release(project string) (gitearelease.ReleaseToFetch, error) {
+	project = strings.Trim(project, "/")
+	if project == "" {
+		return gitearelease.ReleaseToFetch{}, fmt.Errorf("missing repository")
+	}
+	return gitearelease.ReleaseToFetch{
+		BaseURL:  c.baseURL,
+		Provider: c.provider,
+		Token:    c.token,
+		Project:  project,
+	}, nil
+}
+
+// envToken returns the token from the environment: GITEARELEASE_TOKEN, or the
+// conventional variable of the provider the flags point at.
+func envToken(provider, baseURL string) string {
+	if token := os.Getenv("GITEARELEASE_TOKEN"); token != "" {
+		return token
+	}
+	target := strings.ToLower(provider + " " + baseURL)
+	switch {
+	case strings.Contains(target, "gitlab"):
+		return os.Getenv("GITLAB_TOKEN")
+	case strings.Contains(target, "github") || strings.Contains(target, "ghes"):
+		return os.Getenv("GITHUB_TOKEN")
+	default:
+		return os.Getenv("GITEA_TOKEN")
+	}
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
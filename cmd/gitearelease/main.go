@@ -0,0 +1,148 @@
+// Command gitearelease queries releases on Gitea, GitHub and GitLab from the
+// command line: list releases and repositories, download assets, check a
+// version against the latest release and update itself.
+//
+// Usage:
+//
+//	gitearelease <command> [flags] [arguments]
+//
+// Run "gitearelease help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Set with -ldflags "-X main.version=v1.2.3 -X main.commit=<sha>"; the Go
+// toolchain's build information is used when they are empty.
+var (
+	version = ""
+	commit  = ""
+)
+
+// Exit codes. check reports the comparison through exitOlder and exitNewer,
+// verify a mismatch through exitUnverified.
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitOlder      = 10
+	exitNewer      = 11
+	exitUnverified = 12
+)
+
+// command is a subcommand. run returns the process exit code.
+type command struct {
+	usage string
+	short string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands is filled in by init, as the commands refer back to it for their usage.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"releases":    {"releases [flags] <owner/repo>", "List the releases of a repository", runReleases},
+		"latest":      {"latest [flags] <owner/repo>", "Show the latest release of a repository", runLatest},
+		"repos":       {"repos [flags] <owner>", "List the repositories of a user, organization or group", runRepos},
+		"download":    {"download [flags] <owner/repo>", "Download release assets", runDownload},
+		"check":       {"check [flags] <owner/repo> <version>", "Compare a version with the latest release", runCheck},
+		"verify":      {"verify [flags] <owner/repo>", "Check that a commit is the one a release was tagged from", runVerify},
+		"self-update": {"self-update [flags]", "Replace this binary with the latest release", runSelfUpdate},
+		"version":     {"version", "Print the version of this binary", runVersion},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "gitearelease: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gitearelease <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].short)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "gitearelease <command> -h" for the flags of a command.`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Environment:")
+	fmt.Fprintln(w, "  GITEARELEASE_PROVIDER  default for --provider")
+	fmt.Fprintln(w, "  GITEARELEASE_BASE_URL  default for --base-url")
+	fmt.Fprintln(w, "  GITEARELEASE_TOKEN     default for --token; GITEA_TOKEN, GITHUB_TOKEN or")
+	fmt.Fprintln(w, "                         GITLAB_TOKEN are used for the matching provider")
+	fmt.Fprintln(w, "  GITEARELEASE_OUTPUT    default for --output")
+}
+
+// newFlagSet returns a FlagSet for the named command that prints its usage to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: gitearelease %s\n\n%s.\n\nFlags:\n", commands[name].usage, commands[name].short)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args, which may mix flags and positional arguments, and
+// checks the number of positional arguments. It returns them and the exit code
+// to stop with, or -1 to carry on.
+func parseFlags(fs *flag.FlagSet, args []string, positional int) ([]string, int) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, exitOK
+			}
+			return nil, exitUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(rest) != positional {
+		fs.Usage()
+		return nil, exitUsage
+	}
+	return rest, -1
+}
+
+// fail prints err and returns exitError.
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintln(stderr, "gitearelease:", err)
+	return exitError
+}
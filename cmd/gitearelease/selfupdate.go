@@ -0,0 +1,253 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/earentir/gitearelease"
+)
+
+func runSelfUpdate(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("self-update", stderr)
+	provider := fs.String("provider", "", "provider of the repository the binary is released from")
+	baseURL := fs.String("base-url", "https://github.com", "base URL of the forge the binary is released from")
+	token := fs.String("token", "", "API token (default from the environment, as for the other commands)")
+	repo := fs.String("repo", "earentir/gitearelease", "repository the binary is released from")
+	check := fs.Bool("check", false, "only report whether an update is available (exit code 10 if so)")
+	force := fs.Bool("force", false, "install the latest release even if it is not newer")
+	if _, code := parseFlags(fs, args, 0); code >= 0 {
+		return code
+	}
+	if *token == "" {
+		*token = envToken(*provider, *baseURL)
+	}
+	r := gitearelease.ReleaseToFetch{BaseURL: *baseURL, Provider: *provider, Token: *token, Project: *repo}
+
+	own := gitearelease.ReadOwnBuild(version, commit).Own()
+	rel, err := getRelease(r, *repo, "")
+	if err != nil {
+		return fail(stderr, err)
+	}
+	if gitearelease.CompareVersions(gitearelease.VersionStrings{Own: own, Latest: rel.TagName}) >= 0 && !*force {
+		fmt.Fprintf(stdout, "gitearelease %s is up to date (latest release %s)\n", own, rel.TagName)
+		return exitOK
+	}
+	if *check {
+		fmt.Fprintf(stdout, "gitearelease %s is available (current %s)\n", rel.TagName, own)
+		return exitOlder
+	}
+
+	asset, ok := platformAsset(rel.Assets, runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fail(stderr, fmt.Errorf("release %s has no asset for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH))
+	}
+
+	dir, err := os.MkdirTemp("", "gitearelease-update-")
+	if err != nil {
+		return fail(stderr, err)
+	}
+	defer os.RemoveAll(dir)
+
+	file, err := gitearelease.DownloadReleaseAsset(r, asset, dir, "")
+	if err != nil {
+		return fail(stderr, err)
+	}
+	if sums, ok := checksumAsset(rel.Assets, asset.Name); ok {
+		if err := verifyChecksum(r, sums, file, dir); err != nil {
+			return fail(stderr, err)
+		}
+	} else {
+		fmt.Fprintf(stderr, "gitearelease: warning: release %s publishes no checksums; %s is not verified\n", rel.TagName, asset.Name)
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		return fail(stderr, fmt.Errorf("locate executable: %w", err))
+	}
+	if err := replaceExecutable(exe, file); err != nil {
+		return fail(stderr, err)
+	}
+	fmt.Fprintf(stdout, "Updated gitearelease from %s to %s\n", own, rel.TagName)
+	return exitOK
+}
+
+// platformAsset picks the release asset built for goos and goarch, skipping
+// checksums, signatures and other metadata files.
+func platformAsset(assets []gitearelease.Asset, goos, goarch string) (gitearelease.Asset, bool) {
+	for _, asset := range assets {
+		if assetOS, assetArch := gitearelease.AssetPlatform(asset.Name); assetOS == goos && assetArch == goarch {
+			return asset, true
+		}
+	}
+	return gitearelease.Asset{}, false
+}
+
+// checksumAsset finds the checksum file covering name: "name.sha256" or a
+// "checksums.txt"/"SHA256SUMS" style list.
+func checksumAsset(assets []gitearelease.Asset, name string) (gitearelease.Asset, bool) {
+	for _, asset := range assets {
+		if strings.EqualFold(asset.Name, name+".sha256") {
+			return asset, true
+		}
+	}
+	for _, asset := range assets {
+		lower := strings.ToLower(asset.Name)
+		if strings.Contains(lower, "checksums") || strings.Contains(lower, "sha256sums") {
+			return asset, true
+		}
+	}
+	return gitearelease.Asset{}, false
+}
+
+// verifyChecksum downloads the checksum file sums and checks file against it.
+func verifyChecksum(r gitearelease.ReleaseToFetch, sums gitearelease.Asset, file, dir string) error {
+	list, err := gitearelease.DownloadReleaseAsset(r, sums, dir, "checksums")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(list)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(file)
+	var expected string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 1 && strings.HasSuffix(sums.Name, ".sha256"):
+			expected = fields[0]
+		case len(fields) >= 2 && strings.TrimPrefix(fields[len(fields)-1], "*") == name:
+			expected = fields[0]
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("%s has no checksum for %s", sums.Name, name)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, expected) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, expected %s", name, got, expected)
+	}
+	return nil
+}
+
+// replaceExecutable installs the binary from file, which may be a .tar.gz or
+// .zip archive containing it, in place of exe.
+func replaceExecutable(exe, file string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".gitearelease-update-*")
+	if err != nil {
+		return fmt.Errorf("install update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := extractBinary(tmp, file); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("install update: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("install update: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		// A running executable cannot be replaced on Windows, only renamed
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("install update: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("install update: %w", err)
+	}
+	return nil
+}
+
+// extractBinary copies the gitearelease binary from file to w, unpacking it
+// from a .tar.gz or .zip archive when needed.
+func extractBinary(w io.Writer, file string) error {
+	binary := "gitearelease"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	lower := strings.ToLower(file)
+
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", filepath.Base(file), err)
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("read %s: %w", filepath.Base(file), err)
+			}
+			if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binary {
+				_, err := io.Copy(w, tr)
+				return err
+			}
+		}
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", filepath.Base(file), err)
+		}
+		defer zr.Close()
+		for _, zf := range zr.File {
+			if filepath.Base(zf.Name) != binary {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			_, err = io.Copy(w, rc)
+			return err
+		}
+	default:
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	}
+	return fmt.Errorf("%s does not contain %s", filepath.Base(file), binary)
+}
@@ -0,0 +1,197 @@
+package gitearelease
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// defaultEnrichConcurrency bounds the number of concurrent requests made by
+// the optional enrichment passes.
+const defaultEnrichConcurrency = 8
+
+// enrichAssets fills Size, ContentType and CreatedAt of every asset from the headers
+// of a HEAD request to its download URL. Enrichment is best effort: assets
+// whose links fail or do not answer HEAD keep their zero values. The auth
+// headers are only sent to links on the same host as baseURL so tokens never
+// leak to external asset hosts.
+func enrichAssets(releases []Release, baseURL string, headers http.Header) {
+	var assets []*Asset
+	for i := range releases {
+		for j := range releases[i].Assets {
+			assets = append(assets, &releases[i].Assets[j])
+		}
+	}
+
+	forEachLimit(len(assets), defaultEnrichConcurrency, func(i int) {
+		asset := assets[i]
+		if asset.BrowserDownloadURL == "" {
+			return
+		}
+
+		h := http.Header{}
+		if sameHost(asset.BrowserDownloadURL, baseURL) {
+			h = headers
+		}
+		resp, err := doRequest(http.MethodHead, asset.BrowserDownloadURL, h)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return
+		}
+
+		if resp.ContentLength >= 0 {
+			asset.Size = resp.ContentLength
+			asset.Known |= providers.CapAssetSize
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "" {
+			asset.ContentType = ct
+			asset.Known |= providers.CapAssetContentType
+		}
+		if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil && asset.CreatedAt == "" {
+			asset.CreatedAt = lm.UTC().Format(time.RFC3339)
+			asset.CreatedTime = lm.UTC()
+			asset.Known |= providers.CapAssetCreatedAt
+		}
+	})
+}
+
+// countReleases sets an accurate ReleaseCounter on every repository by requesting
+// one release per page and reading the total from the response headers:
+// X-Total on GitLab, the rel="last" page of the Link header on GitHub.
+func countReleases(repos []Repository, provider providers.Provider, baseURL string, headers http.Header, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = defaultEnrichConcurrency
+	}
+
+	errs := make([]error, len(repos))
+	forEachLimit(len(repos), concurrency, func(i int) {
+		owner, name := repoOwnerAndName(repos[i])
+		if _, ok := provider.(*providers.GitLabProvider); ok {
+			// Numeric IDs avoid escaping issues with nested group paths
+			owner, name = strconv.Itoa(repos[i].ID), ""
+		}
+		apiURL := provider.GetReleasesURL(baseURL, owner, name, false) + "?per_page=1"
+
+		count, err := fetchReleaseCount(apiURL, headers)
+		if err != nil {
+			errs[i] = fmt.Errorf("count releases of %q: %w", repos[i].FullName, err)
+			return
+		}
+		repos[i].ReleaseCounter = count
+		repos[i].Capabilities |= providers.CapReleaseCount
+	})
+
+	return errors.Join(errs...)
+}
+
+// fetchReleaseCount requests a one-item page of releases and derives the total.
+func fetchReleaseCount(apiURL string, headers http.Header) (int, error) {
+	resp, err := doRequest(http.MethodGet, apiURL, headers)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &statusError{method: http.MethodGet, url: apiURL, status: resp.Status, code: resp.StatusCode}
+	}
+
+	if total, err := strconv.Atoi(resp.Header.Get("X-Total")); err == nil {
+		return total, nil
+	}
+	if last := lastPage(resp.Header.Get("Link")); last > 0 {
+		return last, nil
+	}
+
+	// A single page: count what was returned
+	var page []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return 0, fmt.Errorf("parse JSON: %w", err)
+	}
+	return len(page), nil
+}
+
+// lastPage returns the page number of the rel="last" entry of an RFC 8288 Link header, or 0.
+func lastPage(link string) int {
+	target := linkTarget(link, "last")
+	if target == "" {
+		return 0
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return 0
+	}
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil {
+		return 0
+	}
+	return page
+}
+
+// linkTarget returns the URL of the entry with the given rel in an RFC 8288 Link header, or "".
+func linkTarget(link, rel string) string {
+	want := `rel="` + rel + `"`
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == want {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// repoOwnerAndName splits a repository's FullName into owner and name.
+func repoOwnerAndName(repo Repository) (string, string) {
+	if idx := strings.LastIndex(repo.FullName, "/"); idx >= 0 {
+		return repo.FullName[:idx], repo.FullName[idx+1:]
+	}
+	return repo.Owner.Login, repo.Name
+}
+
+// forEachLimit calls fn for every index in [0, n) using at most limit goroutines.
+func forEachLimit(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// sameHost reports whether rawURL points at the same host as baseURL.
+func sameHost(rawURL, baseURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	b, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, b.Host)
+}
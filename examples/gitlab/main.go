@@ -41,8 +41,8 @@ func main() {
 	fmt.Printf("  Tag: %s\n", latest.TagName)
 	fmt.Printf("  Name: %s\n", latest.Name)
 	fmt.Printf("  Published: %s\n", latest.PublishedAt)
-	// Note: GitLab doesn't support Draft/Prerelease flags the same way
-	fmt.Printf("  Draft: %v, Prerelease: %v (always false for GitLab)\n", latest.Draft, latest.Prerelease)
+	// Note: GitLab has no Draft/Prerelease flags; Draft marks upcoming releases, Prerelease is inferred from the tag
+	fmt.Printf("  Draft: %v, Prerelease: %v (derived for GitLab)\n", latest.Draft, latest.Prerelease)
 	fmt.Printf("  Assets: %d\n", len(latest.Assets))
 
 	for _, asset := range latest.Assets {
@@ -0,0 +1,145 @@
+package gitearelease
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// newFakeGHES returns a server that mimics the GitHub Enterprise Server REST API under /api/v3.
+func newFakeGHES(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/meta":
+			w.Write([]byte(`{"verifiable_password_authentication": true, "installed_version": "3.12.0"}`))
+		case "/api/v3/repos/corp/tool/releases/latest":
+			if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+				t.Errorf("Expected bearer token, got %q", got)
+			}
+			w.Write([]byte(`{"id": 7, "tag_name": "v2.0.0", "name": "v2.0.0", "body": "", "draft": false, "prerelease": false, "created_at": "2024-01-01T00:00:00Z", "published_at": "2024-01-01T00:00:00Z", "author": {"login": "corp"}, "assets": [{"id": 11, "url": "` + server.URL + `/api/v3/repos/corp/tool/releases/assets/11", "name": "tool-linux-amd64", "size": 6, "download_count": 3, "created_at": "2024-01-01T00:00:00Z", "browser_download_url": "` + server.URL + `/corp/tool/releases/download/v2.0.0/tool-linux-amd64", "content_type": "application/octet-stream"}]}`))
+		case "/api/v3/repos/corp/tool/releases/assets/11":
+			if got := r.Header.Get("Accept"); got != "application/octet-stream" {
+				t.Errorf("Expected Accept application/octet-stream, got %q", got)
+				w.Write([]byte(`{"id": 11}`))
+				return
+			}
+			if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("binary"))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestGetReleases_GHES_Latest(t *testing.T) {
+	server := newFakeGHES(t)
+	defer server.Close()
+
+	releases, err := GetReleases(ReleaseToFetch{
+		BaseURL:  server.URL,
+		User:     "corp",
+		Repo:     "tool",
+		Latest:   true,
+		Provider: "ghes",
+		Token:    "secret",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(releases) != 1 || releases[0].TagName != "v2.0.0" {
+		t.Fatalf("Expected release v2.0.0, got %+v", releases)
+	}
+
+	if len(releases[0].Assets) != 1 || releases[0].Assets[0].URL == "" {
+		t.Fatalf("Expected asset with API URL, got %+v", releases[0].Assets)
+	}
+}
+
+func TestDownloadReleaseAsset_GHES(t *testing.T) {
+	server := newFakeGHES(t)
+	defer server.Close()
+
+	r := ReleaseToFetch{
+		BaseURL: server.URL + "/api/v3", // auto-detected as GHES
+		User:    "corp",
+		Repo:    "tool",
+		Latest:  true,
+		Token:   "secret",
+	}
+
+	releases, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	path, err := DownloadReleaseAsset(r, releases[0].Assets[0], dir, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path != filepath.Join(dir, "tool-linux-amd64") {
+		t.Errorf("Expected file named after the asset, got %s", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %s", err)
+	}
+	if string(content) != "binary" {
+		t.Errorf("Expected binary content, got %q", string(content))
+	}
+}
+
+func TestDetectGitHubEnterprise(t *testing.T) {
+	server := newFakeGHES(t)
+	defer server.Close()
+
+	isGHES, err := DetectGitHubEnterprise(server.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !isGHES {
+		t.Errorf("Expected fake server to be detected as GHES")
+	}
+}
+
+func TestNormalizeBaseURL_GHES(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		provider providers.ProviderType
+		expected string
+	}{
+		{"ghe.example.com", providers.ProviderGitHubEnterprise, "https://ghe.example.com/api/v3"},
+		{"https://ghe.example.com/", providers.ProviderGitHubEnterprise, "https://ghe.example.com/api/v3"},
+		{"https://ghe.example.com/api/v3", providers.ProviderGitHubEnterprise, "https://ghe.example.com/api/v3"},
+		{"https://ghe.example.com/api/v3", resolveProviderType("", "https://ghe.example.com/api/v3"), "https://ghe.example.com/api/v3"},
+		{"github.com", providers.ProviderGitHub, "https://api.github.com"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeBaseURL(tt.baseURL, tt.provider); got != tt.expected {
+			t.Errorf("normalizeBaseURL(%q, %q) = %q, want %q", tt.baseURL, tt.provider, got, tt.expected)
+		}
+	}
+
+	gh := providers.NewGitHubProvider()
+	if got := gh.GetUploadsURL("https://ghe.example.com/api/v3"); got != "https://ghe.example.com/api/uploads" {
+		t.Errorf("Expected GHES uploads URL, got %s", got)
+	}
+	if got := gh.GetUploadsURL("https://api.github.com"); got != "https://uploads.github.com" {
+		t.Errorf("Expected github.com uploads URL, got %s", got)
+	}
+}
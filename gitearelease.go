@@ -3,9 +3,13 @@
 package gitearelease
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,13 +19,34 @@ import (
 	"github.com/earentir/gitearelease/providers"
 )
 
+// ErrNotFound is returned, wrapped, when the requested release or repository does not exist.
+// Test for it with errors.Is.
+var ErrNotFound = errors.New("not found")
+
 // defaultHTTPTimeout is applied to every outbound HTTP operation made by
 // this package unless the caller overrides it through SetHTTPTimeout.
 const defaultHTTPTimeout = 15 * time.Second
 
 // httpClient is shared by every helper in this package so that the timeout
 // applies uniformly. You should rarely need to touch this directly.
-var httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+var httpClient = &http.Client{Timeout: defaultHTTPTimeout, CheckRedirect: checkRedirect}
+
+// maxRedirects matches the limit of the net/http default redirect policy.
+const maxRedirects = 10
+
+// checkRedirect drops the token headers when a redirect leaves the original
+// host, so a release asset served from a CDN or another site never sees them.
+// net/http only strips Authorization, and only outside the original domain.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("Authorization")
+		req.Header.Del("PRIVATE-TOKEN")
+	}
+	return nil
+}
 
 // SetHTTPTimeout overrides the package‑level HTTP timeout. Pass zero or a
 // negative value to restore the built‑in default (15 s). This call is safe
@@ -38,38 +63,34 @@ func SetHTTPTimeout(d time.Duration) {
 /* -------------------------------------------------------------------------- */
 
 // GetReleases returns all releases or only the latest release from a repository.
-// Supports Gitea, GitHub, and GitLab. Provider is auto-detected from BaseURL if not specified.
+// Supports Gitea, GitHub, GitHub Enterprise Server, and GitLab. Provider is auto-detected from BaseURL if not specified.
 func GetReleases(r ReleaseToFetch) ([]Release, error) {
-	// Determine provider - auto-detect if not specified
-	providerType := providers.ProviderType(r.Provider)
-	if providerType == "" {
-		// Auto-detect from BaseURL
-		provider := providers.GetProvider("", r.BaseURL)
-		if provider.DetectProvider(r.BaseURL) {
-			// Determine which provider was detected
-			if providers.NewGitHubProvider().DetectProvider(r.BaseURL) {
-				providerType = providers.ProviderGitHub
-			} else if providers.NewGitLabProvider().DetectProvider(r.BaseURL) {
-				providerType = providers.ProviderGitLab
-			} else {
-				providerType = providers.ProviderGitea // Default for backward compatibility
-			}
-		} else {
-			providerType = providers.ProviderGitea // Default for backward compatibility
-		}
-	}
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
 
-	// Normalize BaseURL for GitHub (add api.github.com if needed)
+	// Normalize BaseURL for the provider (api.github.com, /api/v3, /api/v4)
 	baseURL := normalizeBaseURL(r.BaseURL, providerType)
 
 	// Get the appropriate provider
 	provider := providers.GetProvider(providerType, baseURL)
 
 	// Construct API URL using provider
-	apiURL := provider.GetReleasesURL(baseURL, r.User, r.Repo, r.Latest)
+	user, repo := repoCoordinates(r, providerType)
+	apiURL := provider.GetReleasesURL(baseURL, user, repo, r.Latest)
 
 	// Fetch data
-	apiData, err := fetchData(apiURL)
+	headers := requestHeaders(provider, r.Token)
+	if !r.Latest && (r.Filter != nil || r.MaxResults > 0) {
+		return queryReleases(r, provider, providerType, baseURL, withPageSize(apiURL, providerType), headers)
+	}
+	apiData, err := fetchDataWithHeaders(apiURL, headers)
+	if err != nil && r.Latest && providerType == providers.ProviderGitLab && isStatus(err, http.StatusNotFound) {
+		// GitLab before 15.4 has no permalink/latest; the release list is sorted newest first
+		apiData, err = fetchDataWithHeaders(provider.GetReleasesURL(baseURL, user, repo, false), headers)
+	}
+	if err != nil && r.Latest && r.FallbackToTags && isStatus(err, http.StatusNotFound) {
+		// Gitea and GitHub answer 404 for the latest release of a repository without releases
+		return latestFromTags(r)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +100,9 @@ func GetReleases(r ReleaseToFetch) ([]Release, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(providerReleases) == 0 && r.Latest && r.FallbackToTags {
+		return latestFromTags(r)
+	}
 
 	// Convert from provider types to main package types
 	releases := make([]Release, len(providerReleases))
@@ -86,81 +110,305 @@ func GetReleases(r ReleaseToFetch) ([]Release, error) {
 		releases[i] = convertProviderRelease(pr)
 	}
 
+	if r.EnrichAssets && providerType == providers.ProviderGitLab {
+		enrichAssets(releases, baseURL, headers)
+	}
+	if r.Filter != nil {
+		releases = FilterReleases(releases, r.Filter)
+	}
+
 	return releases, nil
 }
 
-// DownloadBinary downloads a binary from a URL and saves it to a file.
-func DownloadBinary(url, outputDir, filename string) (string, error) {
-	resp, err := httpClient.Get(url)
+// queryReleases reads the release listing at apiURL page by page, keeping the
+// releases that match r.Filter and stopping once r.MaxResults are found.
+func queryReleases(r ReleaseToFetch, provider providers.Provider, providerType providers.ProviderType, baseURL, apiURL string, headers http.Header) ([]Release, error) {
+	keep := r.Filter
+	if keep == nil {
+		keep = AllOf()
+	}
+
+	releases := []Release{}
+	err := eachPage(apiURL, headers, maxPages, func(apiData []byte) (bool, error) {
+		providerReleases, err := provider.NormalizeRelease(apiData, false)
+		if err != nil {
+			return false, err
+		}
+		for _, pr := range providerReleases {
+			rel := convertProviderRelease(pr)
+			if !keep(rel) {
+				continue
+			}
+			releases = append(releases, rel)
+			if r.MaxResults > 0 && len(releases) == r.MaxResults {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("download binary: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download binary: server returned %s", resp.Status)
+	if r.EnrichAssets && providerType == providers.ProviderGitLab {
+		enrichAssets(releases, baseURL, headers)
 	}
+	return releases, nil
+}
 
-	outPath := filepath.Join(outputDir, filename)
-	out, err := os.Create(outPath)
+// GetReleaseByTag returns the release for tag. The error wraps ErrNotFound
+// when the repository has no release for that tag.
+func GetReleaseByTag(r ReleaseToFetch, tag string) (Release, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	finder, ok := provider.(providers.ReleaseFinder)
+	if !ok {
+		return Release{}, fmt.Errorf("provider cannot look up releases by tag: %w", errors.ErrUnsupported)
+	}
+	user, repo := repoCoordinates(r, providerType)
+	return fetchRelease(r, provider, providerType, baseURL, finder.GetReleaseByTagURL(baseURL, user, repo, tag))
+}
+
+// GetReleaseByID returns the release with the given ID. GitLab has no release IDs,
+// so there, as for providers that cannot look releases up, the release list is
+// scanned for the synthetic ID reported by GetReleases.
+// The error wraps ErrNotFound when no release has that ID.
+func GetReleaseByID(r ReleaseToFetch, id int) (Release, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	user, repo := repoCoordinates(r, providerType)
+	if finder, ok := provider.(providers.ReleaseFinder); ok {
+		if apiURL := finder.GetReleaseByIDURL(baseURL, user, repo, id); apiURL != "" {
+			return fetchRelease(r, provider, providerType, baseURL, apiURL)
+		}
+	}
+
+	headers := requestHeaders(provider, r.Token)
+	listURL := provider.GetReleasesURL(baseURL, user, repo, false)
+	pages, err := fetchAllPages(withPageSize(listURL, providerType), headers)
 	if err != nil {
-		return "", fmt.Errorf("create file %q: %w", outPath, err)
+		return Release{}, err
 	}
-	defer out.Close()
+	for _, apiData := range pages {
+		providerReleases, err := provider.NormalizeRelease(apiData, false)
+		if err != nil {
+			return Release{}, err
+		}
+		for _, pr := range providerReleases {
+			if pr.ID == id {
+				return finishRelease(r, providerType, baseURL, headers, pr), nil
+			}
+		}
+	}
+	return Release{}, fmt.Errorf("release %d in %q: %w", id, listURL, ErrNotFound)
+}
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return "", fmt.Errorf("write file %q: %w", outPath, err)
+// fetchRelease fetches and converts the single release at apiURL.
+func fetchRelease(r ReleaseToFetch, provider providers.Provider, providerType providers.ProviderType, baseURL, apiURL string) (Release, error) {
+	headers := requestHeaders(provider, r.Token)
+	apiData, err := fetchDataWithHeaders(apiURL, headers)
+	if err != nil {
+		return Release{}, err
 	}
-	return outPath, nil
+
+	providerReleases, err := provider.NormalizeRelease(apiData, true)
+	if err != nil {
+		return Release{}, err
+	}
+	if len(providerReleases) == 0 {
+		return Release{}, fmt.Errorf("GET %q: %w", apiURL, ErrNotFound)
+	}
+	return finishRelease(r, providerType, baseURL, headers, providerReleases[0]), nil
 }
 
-// GetRepositories returns all repositories of a user and can filter by releases.
-// Supports Gitea, GitHub, and GitLab. Provider is auto-detected from BaseURL if not specified.
-func GetRepositories(r RepositoriesToFetch) ([]Repository, error) {
-	// Determine provider - auto-detect if not specified
-	providerType := providers.ProviderType(r.Provider)
-	if providerType == "" {
-		// Auto-detect from BaseURL
-		if providers.NewGitHubProvider().DetectProvider(r.BaseURL) {
-			providerType = providers.ProviderGitHub
-		} else if providers.NewGitLabProvider().DetectProvider(r.BaseURL) {
-			providerType = providers.ProviderGitLab
+// finishRelease converts pr and applies the optional asset enrichment requested in r.
+func finishRelease(r ReleaseToFetch, providerType providers.ProviderType, baseURL string, headers http.Header, pr providers.Release) Release {
+	releases := []Release{convertProviderRelease(pr)}
+	if r.EnrichAssets && providerType == providers.ProviderGitLab {
+		enrichAssets(releases, baseURL, headers)
+	}
+	return releases[0]
+}
+
+// DownloadBinary downloads a binary from a URL and saves it to a file.
+func DownloadBinary(url, outputDir, filename string) (string, error) {
+	return downloadToFile(url, nil, outputDir, filename)
+}
+
+// DownloadReleaseAsset downloads a release asset using the provider and token in r.
+// GitHub and GitHub Enterprise Server assets are fetched through the API asset
+// endpoint with Accept: application/octet-stream, which works for private
+// repositories and GHES instances where BrowserDownloadURL requires a session.
+// Other providers download BrowserDownloadURL. The token is only sent when
+// the download URL is on the provider's own host.
+func DownloadReleaseAsset(r ReleaseToFetch, asset Asset, outputDir, filename string) (string, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	url := asset.BrowserDownloadURL
+	accept := ""
+	if gh, ok := provider.(*providers.GitHubProvider); ok {
+		url = asset.URL
+		if url == "" && asset.ID != 0 {
+			user, repo := repoCoordinates(r, providerType)
+			url = gh.GetAssetURL(baseURL, user, repo, asset.ID)
+		}
+		if url == "" {
+			url = asset.BrowserDownloadURL
 		} else {
-			providerType = providers.ProviderGitea // Default for backward compatibility
+			accept = "application/octet-stream"
 		}
 	}
+	if url == "" {
+		return "", fmt.Errorf("download asset %q: no download URL", asset.Name)
+	}
 
-	// Normalize BaseURL for GitHub (add api.github.com if needed)
+	headers := http.Header{}
+	if sameHost(url, baseURL) {
+		headers = requestHeaders(provider, r.Token)
+	}
+	if accept != "" {
+		headers.Set("Accept", accept)
+	}
+
+	if filename == "" {
+		filename = asset.Name
+	}
+	return downloadToFile(url, headers, outputDir, filename)
+}
+
+// GetRepositories returns all repositories of a user and can filter by releases.
+// Supports Gitea, GitHub, GitHub Enterprise Server, and GitLab. Provider is auto-detected from BaseURL if not specified.
+func GetRepositories(r RepositoriesToFetch) ([]Repository, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+
+	// Normalize BaseURL for the provider (api.github.com, /api/v3, /api/v4)
 	baseURL := normalizeBaseURL(r.BaseURL, providerType)
 
 	// Get the appropriate provider
 	provider := providers.GetProvider(providerType, baseURL)
 
-	// Construct API URL using provider
-	apiURL := provider.GetRepositoriesURL(baseURL, r.User)
+	if gh, ok := provider.(*providers.GitHubProvider); ok && r.UseGraphQL {
+		pairs, err := fetchGitHubGraphQLRepositories(gh, baseURL, r)
+		if err != nil {
+			return nil, err
+		}
+		return filterWithReleases(repositoriesOf(pairs), r.WithReleases), nil
+	}
 
-	// Fetch data
-	apiData, err := fetchData(apiURL)
+	// Construct API URL using provider
+	apiURL, err := repositoriesURL(provider, baseURL, r)
 	if err != nil {
 		return nil, err
 	}
 
-	// Normalize response using provider
-	providerRepos, err := provider.NormalizeRepositories(apiData)
+	// Fetch every page
+	headers := requestHeaders(provider, r.Token)
+	pages, err := fetchAllPages(withPageSize(apiURL, providerType), headers)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert from provider types to main package types
-	allRepos := make([]Repository, len(providerRepos))
-	for i, pr := range providerRepos {
-		allRepos[i] = convertProviderRepository(pr)
+	// Normalize response using provider and convert to main package types
+	var allRepos []Repository
+	for _, apiData := range pages {
+		providerRepos, err := provider.NormalizeRepositories(apiData)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range providerRepos {
+			repo := convertProviderRepository(pr)
+			if matchesRepositoryFilters(repo, r) {
+				allRepos = append(allRepos, repo)
+			}
+		}
+	}
+
+	if r.CountReleases && providerType != providers.ProviderGitea {
+		if err := countReleases(allRepos, provider, baseURL, headers, r.Concurrency); err != nil {
+			return nil, err
+		}
 	}
 
 	// Filter by releases if requested
-	withReleases := r.WithReleases
+	return filterWithReleases(allRepos, r.WithReleases), nil
+}
+
+// repositoriesURL returns the listing URL for the owner kind selected by r.
+func repositoriesURL(provider providers.Provider, baseURL string, r RepositoriesToFetch) (string, error) {
+	owner, name := r.Owner, r.User
+	if r.Group != "" {
+		owner, name = OwnerOrg, r.Group
+	}
+
+	switch owner {
+	case "", OwnerUser:
+		return provider.GetRepositoriesURL(baseURL, name), nil
+	case OwnerOrg:
+		if gl, ok := provider.(*providers.GitLabProvider); ok {
+			return gl.GetGroupProjectsURL(baseURL, name, r.IncludeSubgroups), nil
+		}
+		lister, ok := provider.(providers.OwnerLister)
+		if !ok {
+			return "", fmt.Errorf("list repositories: %s owner: %w", owner, errors.ErrUnsupported)
+		}
+		return lister.GetOrgRepositoriesURL(baseURL, name), nil
+	case OwnerAuthenticated:
+		if r.Token == "" {
+			return "", fmt.Errorf("list repositories: %s owner requires a Token", owner)
+		}
+		lister, ok := provider.(providers.OwnerLister)
+		if !ok {
+			return "", fmt.Errorf("list repositories: %s owner: %w", owner, errors.ErrUnsupported)
+		}
+		return lister.GetAuthenticatedRepositoriesURL(baseURL), nil
+	default:
+		return "", fmt.Errorf("list repositories: unknown owner kind %q", owner)
+	}
+}
+
+// matchesRepositoryFilters reports whether repo passes the visibility, archived, fork and topic filters of r.
+func matchesRepositoryFilters(repo Repository, r RepositoriesToFetch) bool {
+	switch strings.ToLower(r.Visibility) {
+	case "public":
+		if repo.Private || repo.Internal {
+			return false
+		}
+	case "private":
+		if !repo.Private || repo.Internal {
+			return false
+		}
+	case "internal":
+		if !repo.Internal {
+			return false
+		}
+	}
+	if r.ExcludeArchived && repo.Archived {
+		return false
+	}
+	if r.ExcludeForks && repo.Fork {
+		return false
+	}
+	if r.Topic != "" {
+		for _, topic := range repo.Topics {
+			if strings.EqualFold(topic, r.Topic) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// filterWithReleases keeps only repositories with a positive ReleaseCounter when withReleases is set.
+func filterWithReleases(allRepos []Repository, withReleases bool) []Repository {
 	if !withReleases {
-		return allRepos, nil
+		return allRepos
 	}
 
 	repos := make([]Repository, 0, len(allRepos))
@@ -170,7 +418,26 @@ func GetRepositories(r RepositoriesToFetch) ([]Repository, error) {
 		}
 	}
 
-	return repos, nil
+	return repos
+}
+
+// DetectGitHubEnterprise reports whether baseURL is a GitHub Enterprise Server
+// instance by probing its /api/v3/meta endpoint, which on GHES includes the
+// installed_version field. baseURL may be the bare host or the API root.
+func DetectGitHubEnterprise(baseURL string) (bool, error) {
+	apiURL := normalizeBaseURL(baseURL, providers.ProviderGitHubEnterprise)
+	data, err := fetchData(apiURL + "/meta")
+	if err != nil {
+		return false, err
+	}
+
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return false, fmt.Errorf("parse JSON: %w", err)
+	}
+	return meta.InstalledVersion != "", nil
 }
 
 // TrimVersionPrefix removes common version prefixes from a version string.
@@ -215,11 +482,15 @@ func extractCommitHash(v string) string {
 	return strings.ToLower(hash)
 }
 
-// isBuildVerified reports whether own and latest refer to the same release build.
-// Both sides must carry the same commit hash; missing or mismatched hashes are unverified.
-func isBuildVerified(own, latest string) bool {
-	ownHash := extractCommitHash(own)
-	latestHash := extractCommitHash(latest)
+// isBuildVerified reports whether Own and Latest refer to the same release build.
+// OwnCommit and LatestCommit decide when both are set; otherwise both version
+// strings must carry the same commit hash. Missing or mismatched hashes are unverified.
+func isBuildVerified(v VersionStrings) bool {
+	if v.OwnCommit != "" && v.LatestCommit != "" {
+		return sameCommit(v.OwnCommit, v.LatestCommit)
+	}
+	ownHash := extractCommitHash(v.Own)
+	latestHash := extractCommitHash(v.Latest)
 	if ownHash == "" || latestHash == "" {
 		return false
 	}
@@ -279,28 +550,45 @@ func convertProviderRelease(pr providers.Release) Release {
 			Username:  pr.Author.Username,
 		},
 		Assets: make([]Asset, len(pr.Assets)),
+
+		TargetCommitish: pr.TargetCommitish,
+		CommitSHA:       pr.CommitSHA,
+		UploadURL:       pr.UploadURL,
+		Capabilities:    pr.Capabilities,
 	}
+	rel.CreatedTime, _ = ParseTimestamp(pr.CreatedAt)
+	rel.PublishedTime, _ = ParseTimestamp(pr.PublishedAt)
 
 	for i, pa := range pr.Assets {
-		rel.Assets[i] = Asset{
-			ID:                 pa.ID,
-			Name:               pa.Name,
-			Size:               pa.Size,
-			DownloadCount:      pa.DownloadCount,
-			CreatedAt:          pa.CreatedAt,
-			UUID:               pa.UUID,
-			BrowserDownloadURL: pa.BrowserDownloadURL,
-			Type:               pa.Type,
-		}
+		rel.Assets[i] = convertProviderAsset(pa)
 	}
 
 	return rel
 }
 
+// convertProviderAsset converts providers.Asset to gitearelease.Asset
+func convertProviderAsset(pa providers.Asset) Asset {
+	createdTime, _ := ParseTimestamp(pa.CreatedAt)
+	return Asset{
+		ID:                 pa.ID,
+		Name:               pa.Name,
+		URL:                pa.URL,
+		Size:               pa.Size,
+		DownloadCount:      pa.DownloadCount,
+		CreatedAt:          pa.CreatedAt,
+		UUID:               pa.UUID,
+		BrowserDownloadURL: pa.BrowserDownloadURL,
+		Type:               pa.Type,
+		ContentType:        pa.ContentType,
+		Known:              pa.Known,
+		CreatedTime:        createdTime,
+	}
+}
+
 // convertProviderRepository converts a provider Repository to the main package Repository
 func convertProviderRepository(pr providers.Repository) Repository {
-	createdAt, _ := time.Parse(time.RFC3339, pr.CreatedAt)
-	updatedAt, _ := time.Parse(time.RFC3339, pr.UpdatedAt)
+	createdAt, _ := ParseTimestamp(pr.CreatedAt)
+	updatedAt, _ := ParseTimestamp(pr.UpdatedAt)
 
 	repo := Repository{
 		ID:              pr.ID,
@@ -308,6 +596,7 @@ func convertProviderRepository(pr providers.Repository) Repository {
 		FullName:        pr.FullName,
 		Description:     pr.Description,
 		Private:         pr.Private,
+		Internal:        pr.Internal,
 		Fork:            pr.Fork,
 		Size:            pr.Size,
 		Language:        pr.Language,
@@ -328,6 +617,8 @@ func convertProviderRepository(pr providers.Repository) Repository {
 		HasProjects:     pr.HasProjects,
 		HasReleases:     pr.HasReleases,
 		HasPackages:     pr.HasPackages,
+		Topics:          pr.Topics,
+		Capabilities:    pr.Capabilities,
 	}
 
 	repo.Owner.ID = pr.Owner.ID
@@ -344,6 +635,42 @@ func convertProviderRepository(pr providers.Repository) Repository {
 	return repo
 }
 
+// resolveProviderType returns the explicit provider type, or auto-detects it from baseURL.
+func resolveProviderType(provider, baseURL string) providers.ProviderType {
+	if provider != "" {
+		return providers.ProviderType(strings.ToLower(provider))
+	}
+
+	gh := providers.NewGitHubProvider()
+	switch {
+	case gh.IsEnterprise(baseURL):
+		return providers.ProviderGitHubEnterprise
+	case gh.DetectProvider(baseURL):
+		return providers.ProviderGitHub
+	case providers.NewGitLabProvider().DetectProvider(baseURL):
+		return providers.ProviderGitLab
+	default:
+		return providers.ProviderGitea // Default for backward compatibility
+	}
+}
+
+// repoCoordinates returns the owner and repository name to request for r.
+// A Project path is split at its last slash for Gitea and GitHub; GitLab
+// receives it whole so nested groups and numeric project IDs resolve.
+func repoCoordinates(r ReleaseToFetch, providerType providers.ProviderType) (string, string) {
+	if r.Project == "" {
+		return r.User, r.Repo
+	}
+	project := strings.Trim(r.Project, "/")
+	if providerType == providers.ProviderGitLab {
+		return project, ""
+	}
+	if idx := strings.LastIndex(project, "/"); idx >= 0 {
+		return project[:idx], project[idx+1:]
+	}
+	return r.User, project
+}
+
 // normalizeBaseURL ensures the BaseURL is properly formatted for the provider
 func normalizeBaseURL(baseURL string, providerType providers.ProviderType) string {
 	baseURL = strings.TrimSuffix(baseURL, "/")
@@ -363,6 +690,17 @@ func normalizeBaseURL(baseURL string, providerType providers.ProviderType) strin
 		}
 	}
 
+	// For GitHub Enterprise Server, the REST API lives under /api/v3
+	if providerType == providers.ProviderGitHubEnterprise {
+		if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+			baseURL = "https://" + baseURL
+		}
+		if idx := strings.Index(baseURL, "/api/v3"); idx >= 0 {
+			baseURL = baseURL[:idx]
+		}
+		return baseURL + "/api/v3"
+	}
+
 	// For GitLab, ensure proper API path
 	if providerType == providers.ProviderGitLab {
 		if strings.Contains(baseURL, "gitlab.com") && !strings.Contains(baseURL, "/api/v4") {
@@ -386,20 +724,106 @@ func normalizeBaseURL(baseURL string, providerType providers.ProviderType) strin
 	return baseURL
 }
 
-func fetchData(url string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// maxPages guards pagination against servers that never stop returning a next link.
+const maxPages = 1000
+
+// fetchAllPages GETs url and every following page advertised by the Link header.
+func fetchAllPages(url string, headers http.Header) ([][]byte, error) {
+	return fetchPages(url, headers, maxPages)
+}
+
+// fetchPages follows Link rel="next" headers until the last page or until limit pages are read.
+func fetchPages(url string, headers http.Header, limit int) ([][]byte, error) {
+	var pages [][]byte
+	err := eachPage(url, headers, limit, func(body []byte) (bool, error) {
+		pages = append(pages, body)
+		return true, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("build GET %q: %w", url, err)
+		return nil, err
 	}
+	return pages, nil
+}
 
-	resp, err := httpClient.Do(req)
+// eachPage calls fn with every page like fetchPages, but reads the next page
+// only after fn returns true, so callers can stop paginating early.
+func eachPage(url string, headers http.Header, limit int, fn func(body []byte) (bool, error)) error {
+	for read := 0; url != "" && read < limit; read++ {
+		resp, err := doRequest(http.MethodGet, url, headers)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return &statusError{method: http.MethodGet, url: url, status: resp.Status, code: resp.StatusCode}
+		}
+		if err != nil {
+			return fmt.Errorf("read body: %w", err)
+		}
+
+		more, err := fn(body)
+		if err != nil || !more {
+			return err
+		}
+		url = linkTarget(resp.Header.Get("Link"), "next")
+	}
+	return nil
+}
+
+// withPageSize adds the provider's largest page size to a listing URL so fewer pages are needed.
+func withPageSize(rawURL string, providerType providers.ProviderType) string {
+	param := "per_page"
+	if providerType == providers.ProviderGitea {
+		param = "limit"
+	}
+	u, err := neturl.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("GET %q: %w", url, err)
+		return rawURL
+	}
+	q := u.Query()
+	q.Set(param, strconv.Itoa(pageSize(providerType)))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// pageSize returns the number of items requested per page for the provider.
+func pageSize(providerType providers.ProviderType) int {
+	if providerType == providers.ProviderGitea {
+		return 50 // Gitea's default MAX_RESPONSE_ITEMS
+	}
+	return 100
+}
+
+// requestHeaders returns the headers sent with every API call to provider.
+func requestHeaders(provider providers.Provider, token string) http.Header {
+	headers := http.Header{}
+	if token == "" {
+		return headers
+	}
+	if auth, ok := provider.(providers.Authenticator); ok {
+		name, value := auth.AuthHeader(token)
+		headers.Set(name, value)
+	} else {
+		headers.Set("Authorization", "token "+token)
+	}
+	return headers
+}
+
+func fetchData(url string) ([]byte, error) {
+	return fetchDataWithHeaders(url, nil)
+}
+
+// fetchDataWithHeaders performs a GET request with the given headers and returns the body.
+func fetchDataWithHeaders(url string, headers http.Header) ([]byte, error) {
+	resp, err := doRequest(http.MethodGet, url, headers)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GET %q: server returned %s", url, resp.Status)
+		return nil, &statusError{method: http.MethodGet, url: url, status: resp.Status, code: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -409,6 +833,110 @@ func fetchData(url string) ([]byte, error) {
 	return body, nil
 }
 
+// doRequest sends a bodyless request with the given headers using the shared client.
+// The caller must close the response body.
+func doRequest(method, url string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build %s %q: %w", method, url, err)
+	}
+	for name, values := range headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", method, url, err)
+	}
+	return resp, nil
+}
+
+// sendJSON sends payload as a JSON request body and returns the response body.
+// Any 2xx status is a success.
+func sendJSON(method, url string, headers http.Header, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s %q: %w", method, url, err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build %s %q: %w", method, url, err)
+	}
+	for name, values := range headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &statusError{method: method, url: url, status: resp.Status, code: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return data, nil
+}
+
+// statusError reports an unexpected HTTP status from an API call.
+type statusError struct {
+	method string
+	url    string
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %q: server returned %s", e.method, e.url, e.status)
+}
+
+// Is lets errors.Is(err, ErrNotFound) match a 404 response.
+func (e *statusError) Is(target error) bool {
+	return target == ErrNotFound && e.code == http.StatusNotFound
+}
+
+// isStatus reports whether err is a statusError carrying the given HTTP status code.
+func isStatus(err error, code int) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.code == code
+}
+
+// downloadToFile streams url into outputDir/filename, sending the given headers.
+func downloadToFile(url string, headers http.Header, outputDir, filename string) (string, error) {
+	resp, err := doRequest(http.MethodGet, url, headers)
+	if err != nil {
+		return "", fmt.Errorf("download binary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download binary: server returned %s", resp.Status)
+	}
+
+	outPath := filepath.Join(outputDir, filename)
+	out, err := os.Create(outPath)
+	if err != nil {
+		return "", fmt.Errorf("create file %q: %w", outPath, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return "", fmt.Errorf("write file %q: %w", outPath, err)
+	}
+	return outPath, nil
+}
+
 /* -------------------------------------------------------------------------- */
 /*  CompareVersions and helpers                                               */
 /* -------------------------------------------------------------------------- */
@@ -512,7 +1040,13 @@ func CompareVersionsHelper(v VersionStrings) string {
 		}
 		return v.VersionStrings.Older
 	case 0:
-		if !isBuildVerified(v.Own, v.Latest) {
+		if v.OwnModified {
+			if v.VersionStrings.Dirty == "" {
+				v.VersionStrings.Dirty = "Built from a modified working tree"
+			}
+			return v.VersionStrings.Dirty
+		}
+		if !isBuildVerified(v) {
 			if v.VersionStrings.Unverified == "" {
 				v.VersionStrings.Unverified = "Not a verified build"
 			}
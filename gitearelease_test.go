@@ -2,10 +2,12 @@ package gitearelease
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"testing"
 )
 
@@ -554,3 +556,276 @@ func TestProvider_AutoDetection(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRepositories_Gitea_OrgPaginated(t *testing.T) {
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orgs/acme/repos" {
+			t.Errorf("Expected path /api/v1/orgs/acme/repos, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "50" {
+			t.Errorf("Expected limit=50, got %s", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+mockServer.URL+`/api/v1/orgs/acme/repos?limit=50&page=2>; rel="next", <`+mockServer.URL+`/api/v1/orgs/acme/repos?limit=50&page=2>; rel="last"`)
+			w.Write([]byte(`[{"id": 1, "name": "one", "full_name": "acme/one", "topics": ["cli-tool"]}, {"id": 2, "name": "two", "full_name": "acme/two", "fork": true, "topics": ["cli-tool"]}]`))
+		case "2":
+			w.Write([]byte(`[{"id": 3, "name": "three", "full_name": "acme/three", "archived": true, "topics": ["cli-tool"]}, {"id": 4, "name": "four", "full_name": "acme/four"}]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	repos, err := GetRepositories(RepositoriesToFetch{
+		BaseURL:  mockServer.URL,
+		User:     "acme",
+		Owner:    OwnerOrg,
+		Provider: "gitea",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 4 {
+		t.Fatalf("Expected 4 repos across 2 pages, got %d", len(repos))
+	}
+
+	repos, err = GetRepositories(RepositoriesToFetch{
+		BaseURL:         mockServer.URL,
+		Group:           "acme",
+		Provider:        "gitea",
+		ExcludeArchived: true,
+		ExcludeForks:    true,
+		Topic:           "CLI-tool",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "one" {
+		t.Errorf("Expected only acme/one to pass the filters, got %+v", repos)
+	}
+}
+
+func TestSearchRepositories_Gitea(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/search" {
+			t.Errorf("Expected path /api/v1/repos/search, got %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("q") != "cli-tool" || q.Get("topic") != "true" || q.Get("sort") != "alpha" {
+			t.Errorf("Expected topic search sorted alphabetically, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"ok": true, "data": [
+			{"id": 1, "name": "release-cli", "full_name": "acme/release-cli", "owner": {"login": "acme"}, "language": "Go", "topics": ["cli-tool"]},
+			{"id": 2, "name": "release-py", "full_name": "acme/release-py", "owner": {"login": "acme"}, "language": "Python", "topics": ["cli-tool"]},
+			{"id": 3, "name": "other", "full_name": "acme/other", "owner": {"login": "acme"}, "language": "Go", "topics": ["cli-tool"]}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	repos, err := SearchRepositories(RepositorySearch{
+		RepositoriesToFetch: RepositoriesToFetch{
+			BaseURL:  mockServer.URL,
+			Provider: "gitea",
+			Topic:    "cli-tool",
+		},
+		Query:    "release",
+		Language: "go",
+		Sort:     "name",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "release-cli" {
+		t.Errorf("Expected only release-cli to match, got %+v", repos)
+	}
+}
+
+func TestUploadAsset_Gitea(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/repos/testuser/testrepo/releases/5/assets":
+			if r.URL.Query().Get("name") != "tool.tar.gz" {
+				t.Errorf("Expected name=tool.tar.gz, got %s", r.URL.RawQuery)
+			}
+			if r.ContentLength <= 0 {
+				t.Errorf("Expected a Content-Length, got %d", r.ContentLength)
+			}
+			file, header, err := r.FormFile("attachment")
+			if err != nil {
+				t.Fatalf("Expected attachment form file, got %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			if string(content) != "payload" || header.Filename != "tool.tar.gz" {
+				t.Errorf("Unexpected upload %q named %s", content, header.Filename)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 9, "name": "tool.tar.gz", "size": 7, "download_count": 0, "created_at": "2024-01-01T00:00:00Z", "uuid": "abc", "browser_download_url": "https://gitea.example.com/testuser/testrepo/releases/download/v1.0.0/tool.tar.gz"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/repos/testuser/testrepo/releases/5/assets/9":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "tool.tar.gz")
+	if err := os.WriteFile(path, []byte("payload"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL, User: "testuser", Repo: "testrepo", Provider: "gitea", Token: "secret"}
+	release := Release{ID: 5, TagName: "v1.0.0"}
+
+	asset, err := UploadAsset(r, release, AssetUpload{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asset.ID != 9 || asset.UUID != "abc" || asset.BrowserDownloadURL == "" {
+		t.Errorf("Unexpected asset %+v", asset)
+	}
+
+	if err := DeleteAsset(r, release, asset); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetReleases_Gitea_FallbackToTags(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/testuser/testrepo/releases/latest":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/repos/testuser/testrepo/tags":
+			w.Write([]byte(`[
+				{"name": "nightly", "commit": {"sha": "aaa", "created": "2024-03-01T00:00:00Z"}},
+				{"name": "v2.0.0-rc1", "commit": {"sha": "bbb", "created": "2024-02-01T00:00:00Z"}},
+				{"name": "v1.10.0", "message": "Release 1.10\n", "commit": {"sha": "ccc", "created": "2024-01-15T00:00:00Z"}, "tarball_url": "https://gitea.example.com/testuser/testrepo/archive/v1.10.0.tar.gz", "zipball_url": "https://gitea.example.com/testuser/testrepo/archive/v1.10.0.zip"},
+				{"name": "v1.9.0", "commit": {"sha": "ddd", "created": "2024-01-01T00:00:00Z"}}
+			]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{
+		BaseURL:        mockServer.URL,
+		User:           "testuser",
+		Repo:           "testrepo",
+		Latest:         true,
+		Provider:       "gitea",
+		FallbackToTags: true,
+	}
+
+	tags, err := GetTags(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tags) != 4 || tags[2].CommitSHA != "ccc" || tags[2].Date != "2024-01-15T00:00:00Z" {
+		t.Errorf("Unexpected tags %+v", tags)
+	}
+
+	releases, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(releases))
+	}
+	if releases[0].TagName != "v1.10.0" || releases[0].Body != "Release 1.10" || releases[0].TarballURL == "" {
+		t.Errorf("Expected release built from v1.10.0, got %+v", releases[0])
+	}
+
+	r.FallbackToTags = false
+	if _, err := GetReleases(r); err == nil {
+		t.Errorf("Expected an error without FallbackToTags")
+	}
+}
+
+func TestHighestSemverTag(t *testing.T) {
+	tests := []struct {
+		tags     []string
+		expected string
+	}{
+		{[]string{"v1.2.0", "v1.10.0", "v1.9.9"}, "v1.10.0"},
+		{[]string{"1.0.0", "v2.0.0-beta", "latest"}, "1.0.0"},
+		{[]string{"release-candidate", "nightly"}, ""},
+		{[]string{"v0.1.33-c350f37", "v0.1.32"}, "v0.1.33-c350f37"},
+	}
+
+	for _, tt := range tests {
+		tags := make([]Tag, len(tt.tags))
+		for i, name := range tt.tags {
+			tags[i] = Tag{Name: name}
+		}
+		tag, ok := HighestSemverTag(tags)
+		if ok != (tt.expected != "") || tag.Name != tt.expected {
+			t.Errorf("HighestSemverTag(%v) = %q, %v; want %q", tt.tags, tag.Name, ok, tt.expected)
+		}
+	}
+}
+
+func TestIsBuildVerified_Commits(t *testing.T) {
+	tests := []struct {
+		v        VersionStrings
+		expected bool
+	}{
+		{VersionStrings{Own: "v1.2.3", Latest: "v1.2.3"}, false},
+		{VersionStrings{Own: "v1.2.3", Latest: "v1.2.3", OwnCommit: "c350f37aa1b2", LatestCommit: "c350f37aa1b2c3d4e5f60718293a4b5c6d7e8f90"}, true},
+		{VersionStrings{Own: "v1.2.3", Latest: "v1.2.3", OwnCommit: "d00d00d", LatestCommit: "c350f37aa1b2c3d4e5f60718293a4b5c6d7e8f90"}, false},
+		{VersionStrings{Own: "v1.2.3", Latest: "v1.2.3", OwnCommit: "c35", LatestCommit: "c350f37"}, false},
+		{VersionStrings{Own: "1.2.3-c350f37", Latest: "1.2.3-c350f37"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := isBuildVerified(tt.v); got != tt.expected {
+			t.Errorf("isBuildVerified(%+v) = %v, want %v", tt.v, got, tt.expected)
+		}
+	}
+}
+
+func TestReadOwnBuild(t *testing.T) {
+	defer func(f func() (*debug.BuildInfo, bool)) { readBuildInfo = f }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/tool", Version: "v1.2.3+dirty"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "c350f37aa1b2c3d4e5f60718293a4b5c6d7e8f90"},
+				{Key: "vcs.modified", Value: "true"},
+				{Key: "vcs.time", Value: "2024-01-01T00:00:00Z"},
+			},
+		}, true
+	}
+
+	b := ReadOwnBuild("", "")
+	if b.Version != "v1.2.3" || !b.Modified || b.Time != "2024-01-01T00:00:00Z" {
+		t.Errorf("Unexpected build info %+v", b)
+	}
+	if got := b.Own(); got != "v1.2.3-c350f37" {
+		t.Errorf("Expected Own v1.2.3-c350f37, got %s", got)
+	}
+
+	vs := b.VersionStrings()
+	vs.Latest = "v1.2.3"
+	vs.LatestCommit = "c350f37aa1b2c3d4e5f60718293a4b5c6d7e8f90"
+	if msg := CompareVersionsHelper(vs); msg != "Built from a modified working tree" {
+		t.Errorf("Expected dirty build message, got %q", msg)
+	}
+	vs.OwnModified = false
+	if msg := CompareVersionsHelper(vs); msg != "" {
+		t.Errorf("Expected verified build, got %q", msg)
+	}
+
+	b = ReadOwnBuild("2.0.0", "abcdef1234567")
+	if got := b.Own(); got != "2.0.0-abcdef1" {
+		t.Errorf("Expected ldflags values to win, got %s", got)
+	}
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, true
+	}
+	if got := ReadOwnBuild("", "").Own(); got != "0.0.0" {
+		t.Errorf("Expected development build to report 0.0.0, got %s", got)
+	}
+}
@@ -1,9 +1,16 @@
 package gitearelease
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime/debug"
 	"testing"
+	"time"
 )
 
 func TestGetReleases_GitHub_Success(t *testing.T) {
@@ -215,3 +222,538 @@ func TestGetReleases_GitHub_DraftAndPrerelease(t *testing.T) {
 		t.Errorf("Expected prerelease to be true")
 	}
 }
+
+func TestGetRepositories_GitHub_CountReleases(t *testing.T) {
+	mockData := `[{"id": 1, "name": "many", "full_name": "testuser/many", "has_releases": true, "owner": {"login": "testuser"}}, {"id": 2, "name": "one", "full_name": "testuser/one", "has_releases": true, "owner": {"login": "testuser"}}, {"id": 3, "name": "none", "full_name": "testuser/none", "has_releases": true, "owner": {"login": "testuser"}}]`
+
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/testuser/repos":
+			w.Write([]byte(mockData))
+		case "/repos/testuser/many/releases":
+			if r.URL.Query().Get("per_page") != "1" {
+				t.Errorf("Expected per_page=1, got %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Link", `<`+mockServer.URL+`/repos/testuser/many/releases?per_page=1&page=2>; rel="next", <`+mockServer.URL+`/repos/testuser/many/releases?per_page=1&page=17>; rel="last"`)
+			w.Write([]byte(`[{"id": 1}]`))
+		case "/repos/testuser/one/releases":
+			w.Write([]byte(`[{"id": 1}]`))
+		case "/repos/testuser/none/releases":
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	repos, err := GetRepositories(RepositoriesToFetch{
+		BaseURL:       mockServer.URL,
+		User:          "testuser",
+		WithReleases:  true,
+		Provider:      "github",
+		CountReleases: true,
+		Concurrency:   2,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repos with releases, got %d", len(repos))
+	}
+
+	if repos[0].ReleaseCounter != 17 {
+		t.Errorf("Expected 17 releases from the Link header, got %d", repos[0].ReleaseCounter)
+	}
+
+	if repos[1].ReleaseCounter != 1 {
+		t.Errorf("Expected 1 release, got %d", repos[1].ReleaseCounter)
+	}
+}
+
+func TestGetRepositoriesWithLatestRelease_GitHub_GraphQL(t *testing.T) {
+	pages := []string{
+		`{"data": {"repositoryOwner": {"repositories": {"pageInfo": {"hasNextPage": true, "endCursor": "c1"}, "nodes": [{"databaseId": 1, "name": "cli", "nameWithOwner": "testorg/cli", "url": "https://github.com/testorg/cli", "createdAt": "2023-01-01T00:00:00Z", "updatedAt": "2023-01-02T00:00:00Z", "viewerPermission": "WRITE", "primaryLanguage": {"name": "Go"}, "defaultBranchRef": {"name": "main"}, "owner": {"login": "testorg", "databaseId": 9}, "releases": {"totalCount": 12}, "latestRelease": {"databaseId": 55, "tagName": "v1.2.0", "name": "v1.2.0", "description": "notes", "isDraft": false, "isPrerelease": false, "publishedAt": "2023-01-02T00:00:00Z", "author": {"login": "dev"}, "releaseAssets": {"nodes": [{"name": "cli-linux-amd64", "size": 2048, "downloadCount": 7, "downloadUrl": "https://github.com/testorg/cli/releases/download/v1.2.0/cli-linux-amd64", "contentType": "application/octet-stream"}]}}}]}}}}`,
+		`{"data": {"repositoryOwner": {"repositories": {"pageInfo": {"hasNextPage": false, "endCursor": "c2"}, "nodes": [{"databaseId": 2, "name": "docs", "nameWithOwner": "testorg/docs", "url": "https://github.com/testorg/docs", "owner": {"login": "testorg"}, "releases": {"totalCount": 0}, "latestRelease": null}]}}}}`,
+	}
+
+	calls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("Expected POST /graphql, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer token")
+		}
+
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if calls == 1 && req.Variables["cursor"] != "c1" {
+			t.Errorf("Expected cursor c1 on second page, got %q", req.Variables["cursor"])
+		}
+
+		w.Write([]byte(pages[calls]))
+		calls++
+	}))
+	defer mockServer.Close()
+
+	cfg := RepositoriesToFetch{
+		BaseURL:    mockServer.URL,
+		User:       "testorg",
+		Provider:   "github",
+		Token:      "secret",
+		UseGraphQL: true,
+	}
+
+	pairs, err := GetRepositoriesWithLatestRelease(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if calls != 2 || len(pairs) != 2 {
+		t.Fatalf("Expected 2 repositories over 2 pages, got %d over %d", len(pairs), calls)
+	}
+
+	cli := pairs[0]
+	if cli.Repository.ReleaseCounter != 12 {
+		t.Errorf("Expected accurate ReleaseCounter 12, got %d", cli.Repository.ReleaseCounter)
+	}
+	if !cli.Repository.Permissions.Push || cli.Repository.Permissions.Admin {
+		t.Errorf("Expected WRITE permission to map to push only, got %+v", cli.Repository.Permissions)
+	}
+	if cli.Latest == nil || cli.Latest.TagName != "v1.2.0" || len(cli.Latest.Assets) != 1 {
+		t.Fatalf("Expected latest release v1.2.0 with one asset, got %+v", cli.Latest)
+	}
+	if cli.Latest.Assets[0].DownloadCount != 7 {
+		t.Errorf("Expected download count 7, got %d", cli.Latest.Assets[0].DownloadCount)
+	}
+	if asset := cli.Latest.Assets[0]; asset.ContentType != "application/octet-stream" || !asset.Known.Has(CapabilityAssetContentType) {
+		t.Errorf("Expected known content type application/octet-stream, got %q (known %v)", asset.ContentType, asset.Known.Names())
+	}
+	if pairs[1].Latest != nil {
+		t.Errorf("Expected no latest release for docs")
+	}
+
+	calls = 0
+	cfg.WithReleases = true
+	repos, err := GetRepositories(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "cli" {
+		t.Errorf("Expected only cli to have releases, got %+v", repos)
+	}
+}
+
+func TestGetRepositoriesWithLatestRelease_REST(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/testuser/repos":
+			w.Write([]byte(`[{"id": 1, "name": "a", "full_name": "testuser/a", "has_releases": true}, {"id": 2, "name": "b", "full_name": "testuser/b", "has_releases": true}]`))
+		case "/repos/testuser/a/releases/latest":
+			w.Write([]byte(`{"id": 1, "tag_name": "v0.3.0", "assets": []}`))
+		case "/repos/testuser/b/releases/latest":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	pairs, err := GetRepositoriesWithLatestRelease(RepositoriesToFetch{BaseURL: mockServer.URL, User: "testuser", Provider: "github"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if pairs[0].Latest == nil || pairs[0].Latest.TagName != "v0.3.0" {
+		t.Errorf("Expected latest v0.3.0 for a, got %+v", pairs[0].Latest)
+	}
+	if pairs[1].Latest != nil {
+		t.Errorf("Expected no latest release for b")
+	}
+}
+
+func TestGetRepositories_GitHub_Authenticated(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/repos" {
+			t.Errorf("Expected path /user/repos, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer token")
+		}
+		w.Write([]byte(`[{"id": 1, "name": "public", "full_name": "me/public", "private": false, "visibility": "public"}, {"id": 2, "name": "secret", "full_name": "me/secret", "private": true, "visibility": "private"}, {"id": 3, "name": "corp", "full_name": "me/corp", "private": true, "visibility": "internal"}]`))
+	}))
+	defer mockServer.Close()
+
+	cfg := RepositoriesToFetch{
+		BaseURL:    mockServer.URL,
+		Owner:      OwnerAuthenticated,
+		Provider:   "github",
+		Visibility: "private",
+	}
+	if _, err := GetRepositories(cfg); err == nil {
+		t.Fatalf("Expected an error without a token")
+	}
+
+	cfg.Token = "secret"
+	repos, err := GetRepositories(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "secret" {
+		t.Errorf("Expected only the private repo, got %+v", repos)
+	}
+
+	cfg.Visibility = "internal"
+	repos, err = GetRepositories(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "corp" {
+		t.Errorf("Expected only the internal repo, got %+v", repos)
+	}
+}
+
+func TestSearchRepositories_GitHub(t *testing.T) {
+	requests := 0
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/search/repositories" {
+			t.Errorf("Expected path /search/repositories, got %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if got := q.Get("q"); got != "release topic:cli language:go user:acme" {
+			t.Errorf("Expected qualified query, got %q", got)
+		}
+		if q.Get("sort") != "stars" || q.Get("order") != "desc" || q.Get("per_page") != "100" {
+			t.Errorf("Expected sort=stars&order=desc&per_page=100, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Link", `<`+mockServer.URL+`/search/repositories?page=2>; rel="next"`)
+		w.Write([]byte(`{"total_count": 250, "incomplete_results": false, "items": [
+			{"id": 1, "name": "one", "full_name": "acme/one", "topics": ["cli"]},
+			{"id": 2, "name": "two", "full_name": "acme/two", "topics": ["cli"]}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	repos, err := SearchRepositories(RepositorySearch{
+		RepositoriesToFetch: RepositoriesToFetch{
+			BaseURL:  mockServer.URL,
+			Provider: "github",
+			User:     "acme",
+			Topic:    "cli",
+		},
+		Query:      "release",
+		Language:   "go",
+		Sort:       "stars",
+		Order:      "desc",
+		MaxResults: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected paging to stop after 1 request, got %d", requests)
+	}
+	if len(repos) != 1 || repos[0].FullName != "acme/one" {
+		t.Errorf("Expected the first result only, got %+v", repos)
+	}
+}
+
+func TestSearchRepositories_MaxResultsAfterFilters(t *testing.T) {
+	requests := 0
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+mockServer.URL+`/search/repositories?page=2>; rel="next"`)
+			w.Write([]byte(`{"total_count": 3, "items": [{"id": 1, "name": "one", "full_name": "acme/one", "fork": true}]}`))
+			return
+		}
+		w.Write([]byte(`{"total_count": 3, "items": [
+			{"id": 2, "name": "two", "full_name": "acme/two"},
+			{"id": 3, "name": "three", "full_name": "acme/three"}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	repos, err := SearchRepositories(RepositorySearch{
+		RepositoriesToFetch: RepositoriesToFetch{
+			BaseURL:      mockServer.URL,
+			Provider:     "github",
+			ExcludeForks: true,
+		},
+		Query:      "tool",
+		MaxResults: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if len(repos) != 1 || repos[0].FullName != "acme/two" {
+		t.Errorf("Expected acme/two only, got %+v", repos)
+	}
+}
+
+func TestGetReleaseByTag_GitHub(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/testuser/testrepo/releases/tags/v1.4.2":
+			w.Write([]byte(`{"id": 142, "tag_name": "v1.4.2", "name": "v1.4.2", "assets": []}`))
+		case "/repos/testuser/testrepo/releases/142":
+			w.Write([]byte(`{"id": 142, "tag_name": "v1.4.2", "name": "v1.4.2", "assets": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{
+		BaseURL:  mockServer.URL,
+		User:     "testuser",
+		Repo:     "testrepo",
+		Provider: "github",
+	}
+
+	rel, err := GetReleaseByTag(r, "v1.4.2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.ID != 142 {
+		t.Errorf("Expected release 142, got %d", rel.ID)
+	}
+
+	rel, err = GetReleaseByID(r, 142)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.TagName != "v1.4.2" {
+		t.Errorf("Expected tag v1.4.2, got %s", rel.TagName)
+	}
+
+	if _, err := GetReleaseByTag(r, "v9.9.9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestReleaseWrites_GitHub(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/testuser/testrepo/releases":
+			var payload map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Failed to decode payload: %v", err)
+			}
+			if payload["tag_name"] != "v2.0.0" || payload["target_commitish"] != "main" || payload["draft"] != true {
+				t.Errorf("Unexpected create payload %v", payload)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 200, "tag_name": "v2.0.0", "name": "v2.0.0", "body": "notes", "draft": true}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/testuser/testrepo/releases/200":
+			w.Write([]byte(`{"id": 200, "tag_name": "v2.0.0", "name": "Version 2", "body": "notes", "draft": false}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/testuser/testrepo/releases/200":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{
+		BaseURL:  mockServer.URL,
+		User:     "testuser",
+		Repo:     "testrepo",
+		Provider: "github",
+		Token:    "secret",
+	}
+
+	rel, err := CreateRelease(r, ReleaseSpec{TagName: "v2.0.0", TargetCommitish: "main", Name: "v2.0.0", Body: "notes", Draft: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.ID != 200 || !rel.Draft {
+		t.Errorf("Expected draft release 200, got %+v", rel)
+	}
+
+	rel, err = UpdateRelease(r, rel, ReleaseSpec{Name: "Version 2", Body: "notes"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.Name != "Version 2" || rel.Draft {
+		t.Errorf("Expected published release named Version 2, got %+v", rel)
+	}
+
+	if err := DeleteRelease(r, rel); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestUploadAsset_GitHub_Retries(t *testing.T) {
+	defer func(d time.Duration) { uploadRetryDelay = d }(uploadRetryDelay)
+	uploadRetryDelay = time.Millisecond
+
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/uploads/repos/corp/tool/releases/7/assets" {
+			t.Errorf("Expected GHES uploads path, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if string(body) != "#!/bin/sh\necho hi\n" || r.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Errorf("Unexpected upload %q with type %q", body, r.Header.Get("Content-Type"))
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 70, "name": "install", "size": 18, "content_type": "text/plain", "browser_download_url": "https://ghe.example.com/corp/tool/releases/download/v1.0.0/install"}`))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "install")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho hi\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var sent, total int64
+	asset, err := UploadAsset(ReleaseToFetch{
+		BaseURL:  mockServer.URL + "/api/v3",
+		User:     "corp",
+		Repo:     "tool",
+		Provider: "ghes",
+	}, Release{ID: 7, TagName: "v1.0.0"}, AssetUpload{
+		Path:     path,
+		Progress: func(done, size int64) { sent, total = done, size },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if sent != 18 || total != 18 {
+		t.Errorf("Expected progress 18/18, got %d/%d", sent, total)
+	}
+	if asset.ID != 70 || asset.BrowserDownloadURL == "" {
+		t.Errorf("Unexpected asset %+v", asset)
+	}
+}
+
+func TestUploadAsset_GitHub_UploadURL(t *testing.T) {
+	defer func(d time.Duration) { uploadRetryDelay = d }(uploadRetryDelay)
+	uploadRetryDelay = time.Millisecond
+
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path != "/uploads/repos/o/tool/releases/7/assets" || r.URL.Query().Get("name") != "install" {
+			t.Errorf("Expected the release's upload_url, got %s", r.URL)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "install")
+	if err := os.WriteFile(path, []byte("echo hi\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	release := Release{ID: 7, TagName: "v1.0.0", UploadURL: mockServer.URL + "/uploads/repos/o/tool/releases/7/assets{?name,label}"}
+	_, err := UploadAsset(ReleaseToFetch{BaseURL: "https://api.github.com", User: "o", Repo: "tool", Provider: "github"}, release, AssetUpload{Path: path})
+	if err == nil {
+		t.Fatalf("Expected the 422 to be returned")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected a 422 not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestUploadAsset_GitHub_NoRetryAfterServerError(t *testing.T) {
+	defer func(d time.Duration) { uploadRetryDelay = d }(uploadRetryDelay)
+	uploadRetryDelay = time.Millisecond
+
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		// The asset may have been created before the gateway gave up.
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "install")
+	if err := os.WriteFile(path, []byte("echo hi\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	release := Release{ID: 7, TagName: "v1.0.0", UploadURL: mockServer.URL + "/uploads/repos/o/tool/releases/7/assets{?name,label}"}
+	_, err := UploadAsset(ReleaseToFetch{BaseURL: "https://api.github.com", User: "o", Repo: "tool", Provider: "github"}, release, AssetUpload{Path: path})
+	if err == nil {
+		t.Fatalf("Expected the 502 to be returned")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected a 502 on the upload POST not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestVerifyBuild_GitHub(t *testing.T) {
+	const sha = "c350f37aa1b2c3d4e5f60718293a4b5c6d7e8f90"
+	// The release was created from an older commit; the tag has since moved.
+	const target = "0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c"
+	defer func(f func() (*debug.BuildInfo, bool)) { readBuildInfo = f }(readBuildInfo)
+	modified := "false"
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: sha},
+			{Key: "vcs.modified", Value: modified},
+		}}, true
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/testuser/testrepo/releases/latest":
+			w.Write([]byte(`{"id": 1, "tag_name": "v1.2.3", "target_commitish": "` + target + `", "assets": []}`))
+		case "/repos/testuser/testrepo/commits/v1.2.3":
+			w.Write([]byte(`{"sha": "` + sha + `"}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL, User: "testuser", Repo: "testrepo", Latest: true, Provider: "github"}
+	releases, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if releases[0].TargetCommitish != target || releases[0].CommitSHA != "" {
+		t.Errorf("Expected target without commit SHA, got %+v", releases[0])
+	}
+
+	verified, err := VerifyBuild(r, releases[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !verified {
+		t.Errorf("Expected build of %s to be verified", sha)
+	}
+
+	modified = "true"
+	if verified, _ := VerifyBuild(r, releases[0]); verified {
+		t.Errorf("Expected a modified build not to be verified")
+	}
+}
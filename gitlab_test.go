@@ -1,10 +1,18 @@
 package gitearelease
 
 import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+
+	"github.com/earentir/gitearelease/providers"
 )
 
 func TestGetReleases_GitLab_Success(t *testing.T) {
@@ -229,15 +237,19 @@ func TestGetRepositories_GitLab_WithReleases(t *testing.T) {
 }
 
 func TestGetReleases_GitLab_NoLatestEndpoint(t *testing.T) {
-	// Test that GitLab fetches all releases when Latest=true
-	// (since GitLab doesn't have a /latest endpoint)
+	// Test that GitLab falls back to fetching all releases when Latest=true
+	// and the instance predates /releases/permalink/latest (GitLab < 15.4)
 	mockData := `[{"tag_name": "v1.0.0", "name": "Release 1.0.0", "description": "Latest release", "created_at": "2023-01-02T00:00:00Z", "released_at": "2023-01-02T00:00:00Z", "author": {"id": 1, "username": "testuser", "name": "Test User", "email": "test@example.com", "avatar_url": "https://gitlab.com/testuser.png"}, "commit": {"id": "abc123", "short_id": "abc123", "title": "Latest commit", "created_at": "2023-01-02T00:00:00Z", "message": "Latest commit", "author_name": "Test User", "author_email": "test@example.com"}, "milestones": [], "commit_path": "/testuser/testrepo/-/commit/abc123", "tag_path": "/testuser/testrepo/-/tags/v1.0.0", "assets": {"count": 0, "links": [], "sources": []}, "evidences": [], "_links": {"self": "https://gitlab.com/api/v4/projects/testuser%2Ftestrepo/releases/v1.0.0", "edit_url": "https://gitlab.com/testuser/testrepo/-/releases/v1.0.0/edit"}}, {"tag_name": "v0.9.0", "name": "Release 0.9.0", "description": "Older release", "created_at": "2023-01-01T00:00:00Z", "released_at": "2023-01-01T00:00:00Z", "author": {"id": 1, "username": "testuser", "name": "Test User", "email": "test@example.com", "avatar_url": "https://gitlab.com/testuser.png"}, "commit": {"id": "def456", "short_id": "def456", "title": "Older commit", "created_at": "2023-01-01T00:00:00Z", "message": "Older commit", "author_name": "Test User", "author_email": "test@example.com"}, "milestones": [], "commit_path": "/testuser/testrepo/-/commit/def456", "tag_path": "/testuser/testrepo/-/tags/v0.9.0", "assets": {"count": 0, "links": [], "sources": []}, "evidences": [], "_links": {"self": "https://gitlab.com/api/v4/projects/testuser%2Ftestrepo/releases/v0.9.0", "edit_url": "https://gitlab.com/testuser/testrepo/-/releases/v0.9.0/edit"}}]`
 
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Should still call the same endpoint (no /latest)
+		// The permalink endpoint is missing, so the list endpoint is called next
 		// normalizeBaseURL adds /api/v4, then GetReleasesURL adds /projects/...
 		// Note: URL may decode %2F to /, so we check for either
-		if !strings.Contains(r.URL.Path, "/projects/") || !strings.Contains(r.URL.Path, "/releases") {
+		if strings.HasSuffix(r.URL.Path, "/releases/permalink/latest") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !strings.Contains(r.URL.Path, "/projects/") || !strings.HasSuffix(r.URL.Path, "/releases") {
 			t.Errorf("Expected path containing /projects/.../releases, got %s", r.URL.Path)
 		}
 		w.WriteHeader(http.StatusOK)
@@ -268,3 +280,572 @@ func TestGetReleases_GitLab_NoLatestEndpoint(t *testing.T) {
 		t.Errorf("Expected tag v1.0.0, got %s", releases[0].TagName)
 	}
 }
+
+func TestGetReleases_GitLab_NestedProjectPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		fetch   ReleaseToFetch
+		wantURI string
+	}{
+		{
+			name:    "subgroup in User",
+			fetch:   ReleaseToFetch{User: "group/subgroup", Repo: "project"},
+			wantURI: "/api/v4/projects/group%2Fsubgroup%2Fproject/releases",
+		},
+		{
+			name:    "full Project path",
+			fetch:   ReleaseToFetch{Project: "group/sub group/my.project"},
+			wantURI: "/api/v4/projects/group%2Fsub%20group%2Fmy.project/releases",
+		},
+		{
+			name:    "numeric Project ID",
+			fetch:   ReleaseToFetch{Project: "4242"},
+			wantURI: "/api/v4/projects/4242/releases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.RequestURI != tt.wantURI {
+					t.Errorf("Expected request URI %s, got %s", tt.wantURI, r.RequestURI)
+				}
+				w.Write([]byte(`[]`))
+			}))
+			defer mockServer.Close()
+
+			tt.fetch.BaseURL = mockServer.URL
+			tt.fetch.Provider = "gitlab"
+			if _, err := GetReleases(tt.fetch); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetRepositories_GitLab_Group(t *testing.T) {
+	mockData := `[{"id": 7, "name": "tool", "path": "tool", "path_with_namespace": "platform/infra/tool", "visibility": "internal", "web_url": "https://gitlab.example.com/platform/infra/tool", "created_at": "2023-01-01T00:00:00Z", "last_activity_at": "2023-01-02T00:00:00Z", "permissions": {"project_access": null, "group_access": {"access_level": 30}}}]`
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantURI := "/api/v4/groups/platform%2Finfra/projects?include_subgroups=true&per_page=100"
+		if r.RequestURI != wantURI {
+			t.Errorf("Expected request URI %s, got %s", wantURI, r.RequestURI)
+		}
+		w.Write([]byte(mockData))
+	}))
+	defer mockServer.Close()
+
+	repos, err := GetRepositories(RepositoriesToFetch{
+		BaseURL:          mockServer.URL,
+		Group:            "platform/infra",
+		IncludeSubgroups: true,
+		Provider:         "gitlab",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(repos) != 1 || repos[0].FullName != "platform/infra/tool" {
+		t.Fatalf("Expected platform/infra/tool, got %+v", repos)
+	}
+
+	if !repos[0].Internal || repos[0].Private {
+		t.Errorf("Expected internal visibility, got private=%v internal=%v", repos[0].Private, repos[0].Internal)
+	}
+}
+
+func TestGetReleases_GitLab_PermalinkLatest(t *testing.T) {
+	mockData := `{"tag_name": "v2.0.0-rc.1", "name": "2.0.0 RC1", "description": "## Features\n\n- New thing\n- Other thing", "created_at": "2023-02-01T00:00:00Z", "released_at": "2099-01-01T00:00:00Z", "upcoming_release": true, "author": {"username": "testuser"}, "assets": {"count": 0, "links": [], "sources": []}, "_links": {"self": "https://gitlab.com/api/v4/projects/testuser%2Ftestrepo/releases/v2.0.0-rc.1"}}`
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/releases/permalink/latest") {
+			t.Errorf("Expected permalink/latest path, got %s", r.URL.Path)
+		}
+		w.Write([]byte(mockData))
+	}))
+	defer mockServer.Close()
+
+	releases, err := GetReleases(ReleaseToFetch{
+		BaseURL:  mockServer.URL,
+		User:     "testuser",
+		Repo:     "testrepo",
+		Latest:   true,
+		Provider: "gitlab",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(releases) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(releases))
+	}
+
+	rel := releases[0]
+	if !rel.Draft {
+		t.Errorf("Expected upcoming release to be reported as draft")
+	}
+
+	if !rel.Prerelease {
+		t.Errorf("Expected v2.0.0-rc.1 to be a prerelease")
+	}
+
+	if rel.Body != "## Features\n\n- New thing\n- Other thing" {
+		t.Errorf("Expected Markdown description to be preserved, got %q", rel.Body)
+	}
+}
+
+func TestGetReleases_GitLab_PrereleaseInference(t *testing.T) {
+	tests := []struct {
+		tag        string
+		prerelease bool
+	}{
+		{"v1.0.0", false},
+		{"v1.0.0-beta", true},
+		{"1.2.0-rc.2+build.5", true},
+		{"v0.1.33-c350f37", false},
+		{"nightly-2023", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[{"tag_name": "` + tt.tag + `", "assets": {"links": [], "sources": []}}]`))
+			}))
+			defer mockServer.Close()
+
+			releases, err := GetReleases(ReleaseToFetch{BaseURL: mockServer.URL, User: "u", Repo: "r", Provider: "gitlab"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if releases[0].Prerelease != tt.prerelease {
+				t.Errorf("Prerelease for %s = %v, want %v", tt.tag, releases[0].Prerelease, tt.prerelease)
+			}
+		})
+	}
+}
+
+func TestGetReleases_GitLab_StableIDs(t *testing.T) {
+	mockData := `[{"tag_name": "v1.0.0", "tag_path": "/group/app/-/tags/v1.0.0", "assets": {"links": [], "sources": []}}, {"tag_name": "v1.0.0", "tag_path": "/group/other/-/tags/v1.0.0", "assets": {"links": [], "sources": []}}]`
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(mockData))
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL, User: "group", Repo: "app", Provider: "gitlab"}
+	first, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first[0].ID != second[0].ID {
+		t.Errorf("Expected stable IDs, got %d and %d", first[0].ID, second[0].ID)
+	}
+
+	if first[0].ID == first[1].ID {
+		t.Errorf("Expected the same tag in different projects to get different IDs")
+	}
+
+	if first[0].ID != providers.GitLabReleaseID("group/app", "v1.0.0") {
+		t.Errorf("Expected ID %d, got %d", providers.GitLabReleaseID("group/app", "v1.0.0"), first[0].ID)
+	}
+}
+
+func TestGitLabReleaseID_IntSize(t *testing.T) {
+	h := fnv.New64a()
+	h.Write([]byte("group/app\x00v1.0.0"))
+	expected := int(h.Sum64() & (uint64(1)<<(strconv.IntSize-1) - 1))
+
+	id := providers.GitLabReleaseID("group/app", "v1.0.0")
+	if id != expected {
+		t.Errorf("Expected the hash masked to %d bits (%d), got %d", strconv.IntSize-1, expected, id)
+	}
+	if id < 0 {
+		t.Errorf("Expected a non-negative ID, got %d", id)
+	}
+}
+
+func TestGetReleases_GitLab_EnrichAssets(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "" {
+			t.Errorf("Token must not be sent to external asset hosts")
+		}
+		w.Header().Set("Content-Length", "99")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer external.Close()
+
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+				t.Errorf("Expected token on same-host asset link")
+			}
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Header().Set("Content-Length", "2048")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2023 15:04:05 GMT")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Write([]byte(`[{"tag_name": "v1.0.0", "assets": {"links": [{"id": 1, "name": "app.tar.gz", "direct_asset_url": "` + mockServer.URL + `/downloads/app.tar.gz", "link_type": "package"}, {"id": 2, "name": "app.zip", "direct_asset_url": "` + external.URL + `/app.zip", "link_type": "other"}], "sources": []}}]`))
+	}))
+	defer mockServer.Close()
+
+	releases, err := GetReleases(ReleaseToFetch{
+		BaseURL:      mockServer.URL,
+		User:         "group",
+		Repo:         "app",
+		Provider:     "gitlab",
+		Token:        "secret",
+		EnrichAssets: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	asset := releases[0].Assets[0]
+	if asset.Size != 2048 {
+		t.Errorf("Expected size 2048, got %d", asset.Size)
+	}
+	if asset.ContentType != "application/gzip" || asset.Type != "package" {
+		t.Errorf("Expected Content-Type application/gzip and link type package, got %s and %s", asset.ContentType, asset.Type)
+	}
+	if asset.CreatedAt != "2023-01-02T15:04:05Z" {
+		t.Errorf("Expected CreatedAt from Last-Modified, got %s", asset.CreatedAt)
+	}
+	if !asset.Known.Has(CapabilityAssetSize|CapabilityAssetContentType|CapabilityAssetCreatedAt) || asset.Known.Has(CapabilityAssetDownloads) {
+		t.Errorf("Expected enriched fields to be known and downloads unknown, got %v", asset.Known)
+	}
+
+	if releases[0].Assets[1].Size != 99 {
+		t.Errorf("Expected external asset size 99, got %d", releases[0].Assets[1].Size)
+	}
+}
+
+func TestGetRepositories_GitLab_CountReleases(t *testing.T) {
+	mockData := `[{"id": 11, "name": "tool", "path_with_namespace": "group/sub/tool"}, {"id": 12, "name": "docs", "path_with_namespace": "group/sub/docs"}]`
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/users/testuser/projects":
+			w.Write([]byte(mockData))
+		case "/api/v4/projects/11/releases":
+			w.Header().Set("X-Total", "5")
+			w.Write([]byte(`[{"tag_name": "v5"}]`))
+		case "/api/v4/projects/12/releases":
+			w.Header().Set("X-Total", "0")
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	repos, err := GetRepositories(RepositoriesToFetch{
+		BaseURL:       mockServer.URL,
+		User:          "testuser",
+		WithReleases:  true,
+		Provider:      "gitlab",
+		CountReleases: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(repos) != 1 || repos[0].Name != "tool" {
+		t.Fatalf("Expected only tool to have releases, got %+v", repos)
+	}
+
+	if repos[0].ReleaseCounter != 5 {
+		t.Errorf("Expected ReleaseCounter 5 from X-Total, got %d", repos[0].ReleaseCounter)
+	}
+}
+
+func TestSearchRepositories_GitLab(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "/api/v4/groups/platform%2Ftools/projects?include_subgroups=true&order_by=last_activity_at&per_page=100&search=release&sort=asc&topic=cli&with_programming_language=Go"
+		if r.RequestURI != expected {
+			t.Errorf("Expected request %s, got %s", expected, r.RequestURI)
+		}
+		w.Write([]byte(`[{"id": 42, "name": "release-cli", "path_with_namespace": "platform/tools/release-cli", "topics": ["cli"]}]`))
+	}))
+	defer mockServer.Close()
+
+	repos, err := SearchRepositories(RepositorySearch{
+		RepositoriesToFetch: RepositoriesToFetch{
+			BaseURL:  mockServer.URL + "/api/v4",
+			Provider: "gitlab",
+			Group:    "platform/tools",
+			Topic:    "cli",
+		},
+		Query:    "release",
+		Language: "Go",
+		Sort:     "updated",
+		Order:    "asc",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].ID != 42 {
+		t.Errorf("Expected project 42, got %+v", repos)
+	}
+}
+
+func TestSearchRepositories_GitLabUser(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "/api/v4/users/jdoe/projects?per_page=100&search=release"
+		if r.RequestURI != expected {
+			t.Errorf("Expected request %s, got %s", expected, r.RequestURI)
+		}
+		w.Write([]byte(`[{"id": 7, "name": "release-notes", "path_with_namespace": "jdoe/release-notes"}]`))
+	}))
+	defer mockServer.Close()
+
+	repos, err := SearchRepositories(RepositorySearch{
+		RepositoriesToFetch: RepositoriesToFetch{
+			BaseURL:  mockServer.URL + "/api/v4",
+			Provider: "gitlab",
+			User:     "jdoe",
+		},
+		Query: "release",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repos) != 1 || repos[0].ID != 7 {
+		t.Errorf("Expected project 7, got %+v", repos)
+	}
+}
+
+func TestGetReleaseByTagAndID_GitLab(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fproject/releases/release%2F1.0":
+			w.Write([]byte(`{"tag_name": "release/1.0", "name": "1.0", "tag_path": "/group/project/-/tags/release/1.0"}`))
+		case "/api/v4/projects/group%2Fproject/releases":
+			w.Write([]byte(`[{"tag_name": "v2.0.0", "name": "2.0", "tag_path": "/group/project/-/tags/v2.0.0"}, {"tag_name": "v1.0.0", "name": "1.0", "tag_path": "/group/project/-/tags/v1.0.0"}]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{
+		BaseURL:  mockServer.URL + "/api/v4",
+		Project:  "group/project",
+		Provider: "gitlab",
+	}
+
+	rel, err := GetReleaseByTag(r, "release/1.0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.TagName != "release/1.0" {
+		t.Errorf("Expected tag release/1.0, got %s", rel.TagName)
+	}
+
+	id := providers.GitLabReleaseID("group/project", "v1.0.0")
+	rel, err = GetReleaseByID(r, id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.TagName != "v1.0.0" {
+		t.Errorf("Expected tag v1.0.0, got %s", rel.TagName)
+	}
+
+	if _, err := GetReleaseByID(r, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestReleaseWrites_GitLab(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/releases":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"tag_name": "v1.0.0-rc.1", "name": "RC 1", "description": "notes", "tag_path": "/group/project/-/tags/v1.0.0-rc.1"}`))
+		case r.Method == http.MethodPut && r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/releases/v1.0.0-rc.1":
+			w.Write([]byte(`{"tag_name": "v1.0.0-rc.1", "name": "RC one", "description": "notes", "tag_path": "/group/project/-/tags/v1.0.0-rc.1"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{
+		BaseURL:  mockServer.URL + "/api/v4",
+		Project:  "group/project",
+		Provider: "gitlab",
+	}
+
+	if _, err := CreateRelease(r, ReleaseSpec{TagName: "v1.0.0", Draft: true}); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for a draft, got %v", err)
+	}
+	if _, err := CreateRelease(r, ReleaseSpec{TagName: "v1.0.0", Prerelease: true}); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for a prerelease on a stable tag, got %v", err)
+	}
+
+	rel, err := CreateRelease(r, ReleaseSpec{TagName: "v1.0.0-rc.1", Name: "RC 1", Body: "notes", Prerelease: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !rel.Prerelease {
+		t.Errorf("Expected prerelease, got %+v", rel)
+	}
+
+	rel, err = UpdateRelease(r, rel, ReleaseSpec{Name: "RC one", Body: "notes", Prerelease: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rel.Name != "RC one" {
+		t.Errorf("Expected name RC one, got %s", rel.Name)
+	}
+
+	if _, err := UpdateRelease(r, rel, ReleaseSpec{Name: "RC one", Body: "new notes"}); err != nil {
+		t.Errorf("Expected a name and body edit of a prerelease tag to succeed, got %v", err)
+	}
+
+	if _, err := UpdateRelease(r, rel, ReleaseSpec{TagName: "v1.0.0-rc.2", Prerelease: true}); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported when moving the tag, got %v", err)
+	}
+}
+
+func TestUploadAsset_GitLab(t *testing.T) {
+	var serverURL string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/packages/generic/project/v1.0.0/tool.zip":
+			if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+				t.Errorf("Expected PRIVATE-TOKEN header, got %q", got)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"message": "201 Created"}`))
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/releases/v1.0.0/assets/links":
+			var payload map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Failed to decode payload: %v", err)
+			}
+			expected := serverURL + "/api/v4/projects/group%2Fproject/packages/generic/project/v1.0.0/tool.zip"
+			if payload["url"] != expected || payload["link_type"] != "package" {
+				t.Errorf("Unexpected link payload %v", payload)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 3, "name": "tool.zip", "url": "` + payload["url"] + `", "link_type": "package"}`))
+		case r.Method == http.MethodDelete && r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/releases/v1.0.0/assets/links/3":
+			w.Write([]byte(`{"id": 3}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	serverURL = mockServer.URL
+
+	path := filepath.Join(t.TempDir(), "tool.zip")
+	if err := os.WriteFile(path, []byte("zipdata"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL + "/api/v4", Project: "group/project", Provider: "gitlab", Token: "secret"}
+	release := Release{TagName: "v1.0.0"}
+
+	asset, err := UploadAsset(r, release, AssetUpload{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asset.ID != 3 || asset.Size != 7 || !strings.HasSuffix(asset.BrowserDownloadURL, "/tool.zip") {
+		t.Errorf("Unexpected asset %+v", asset)
+	}
+
+	if err := DeleteAsset(r, release, asset); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetTags_GitLab(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/repository/tags" {
+			t.Errorf("Unexpected path %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`[{"name": "v1.0.0", "message": "", "commit": {"id": "0123456789abcdef0123456789abcdef01234567", "committed_date": "2024-01-01T10:00:00.000+00:00"}}]`))
+	}))
+	defer mockServer.Close()
+
+	tags, err := GetTags(ReleaseToFetch{BaseURL: mockServer.URL + "/api/v4", Project: "group/project", Provider: "gitlab"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tags) != 1 {
+		t.Fatalf("Expected 1 tag, got %d", len(tags))
+	}
+
+	tag := tags[0]
+	if tag.CommitSHA != "0123456789abcdef0123456789abcdef01234567" || tag.Date == "" {
+		t.Errorf("Expected commit SHA and date, got %+v", tag)
+	}
+	expected := mockServer.URL + "/api/v4/projects/group%2Fproject/repository/archive.tar.gz?sha=v1.0.0"
+	if tag.TarballURL != expected {
+		t.Errorf("Expected tarball URL %s, got %s", expected, tag.TarballURL)
+	}
+}
+
+func TestGetReleases_GitLab_CommitSHA(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.0.0", "commit": {"id": "0123456789abcdef0123456789abcdef01234567"}, "tag_path": "/group/project/-/tags/v1.0.0"}`))
+	}))
+	defer mockServer.Close()
+
+	r := ReleaseToFetch{BaseURL: mockServer.URL + "/api/v4", Project: "group/project", Latest: true, Provider: "gitlab"}
+	releases, err := GetReleases(r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sha, err := ResolveReleaseCommit(r, releases[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sha != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("Expected the release's commit.id, got %q", sha)
+	}
+}
+
+func TestDownloadReleaseAsset_GitLab_TokenStaysOnHost(t *testing.T) {
+	assetHost := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "" {
+			t.Errorf("Expected no token on the asset host, got %q", got)
+		}
+		w.Write([]byte("binary"))
+	}))
+	defer assetHost.Close()
+
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			t.Errorf("Expected token on the GitLab host, got %q", got)
+		}
+		http.Redirect(w, r, assetHost.URL+"/tool", http.StatusFound)
+	}))
+	defer gitlab.Close()
+
+	r := ReleaseToFetch{BaseURL: gitlab.URL + "/api/v4", Project: "group/project", Provider: "gitlab", Token: "secret"}
+	for _, url := range []string{assetHost.URL + "/tool", gitlab.URL + "/group/project/-/releases/v1.0.0/downloads/tool"} {
+		path, err := DownloadReleaseAsset(r, Asset{Name: "tool", BrowserDownloadURL: url}, t.TempDir(), "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read downloaded file: %s", err)
+		}
+		if string(content) != "binary" {
+			t.Errorf("Expected binary content, got %q", string(content))
+		}
+	}
+}
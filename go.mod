@@ -2,4 +2,7 @@ module github.com/earentir/gitearelease
 
 go 1.26.4
 
-require github.com/earentir/identifybin v0.0.2
+require (
+	github.com/earentir/identifybin v0.0.2
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,223 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// MirrorRelease copies the source release for tag, with its assets, to target
+// unless it is already there. The tag and draft filters in opts do not apply.
+// Assets missing from an existing target release are uploaded, so an
+// interrupted run can simply be repeated.
+func MirrorRelease(source, target ReleaseToFetch, tag string, opts MirrorOptions) (MirrorPlan, error) {
+	var plan MirrorPlan
+
+	rel, err := GetReleaseByTag(source, tag)
+	if err != nil {
+		return plan, err
+	}
+
+	existing, err := GetReleaseByTag(target, tag)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return plan, err
+	}
+	var current *Release
+	if err == nil {
+		current = &existing
+	}
+
+	err = mirrorOne(&plan, source, target, rel, current, opts)
+	return plan, err
+}
+
+// SyncReleases mirrors every source release that matches the tag filters and
+// is missing on target, oldest first so the target's latest release matches
+// the source. Existing target releases gain any assets they lack. Releases the
+// target cannot represent, such as drafts on GitLab, are skipped and reported
+// in the plan as MirrorSkipped.
+func SyncReleases(source, target ReleaseToFetch, opts MirrorOptions) (MirrorPlan, error) {
+	var plan MirrorPlan
+
+	sourceReleases, err := fetchAllReleases(source)
+	if err != nil {
+		return plan, err
+	}
+	targetReleases, err := fetchAllReleases(target)
+	if err != nil {
+		return plan, err
+	}
+
+	byTag := make(map[string]*Release, len(targetReleases))
+	for i := range targetReleases {
+		byTag[targetReleases[i].TagName] = &targetReleases[i]
+	}
+
+	for i := len(sourceReleases) - 1; i >= 0; i-- {
+		rel := sourceReleases[i]
+		if !opts.matchesTag(rel.TagName) || (rel.Draft && !opts.IncludeDrafts) {
+			continue
+		}
+		if err := mirrorOne(&plan, source, target, rel, byTag[rel.TagName], opts); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// mirrorOne plans, and unless DryRun applies, the steps that bring current up to rel.
+// current is nil when target has no release for the tag.
+func mirrorOne(plan *MirrorPlan, source, target ReleaseToFetch, rel Release, current *Release, opts MirrorOptions) error {
+	existed := current != nil
+	present := map[string]bool{}
+	if !existed {
+		spec := ReleaseSpec{
+			TagName:         rel.TagName,
+			TargetCommitish: opts.TargetCommitish,
+			Name:            rel.Name,
+			Body:            rel.Body,
+			Draft:           rel.Draft,
+			Prerelease:      rel.Prerelease,
+		}
+		if err := checkCreateRelease(target, spec); errors.Is(err, errors.ErrUnsupported) {
+			plan.Actions = append(plan.Actions, MirrorAction{Kind: MirrorSkipped, Tag: rel.TagName, Reason: err.Error()})
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("mirror %s: %w", rel.TagName, err)
+		}
+
+		plan.add(MirrorCreateRelease, rel.TagName, "")
+		if !opts.DryRun {
+			created, err := CreateRelease(target, spec)
+			if err != nil {
+				return fmt.Errorf("mirror %s: %w", rel.TagName, err)
+			}
+			current = &created
+		}
+	} else {
+		for _, asset := range current.Assets {
+			present[asset.Name] = true
+		}
+	}
+
+	uploads := 0
+	for _, asset := range rel.Assets {
+		if opts.SkipAssets || present[asset.Name] {
+			continue
+		}
+		uploads++
+		plan.add(MirrorUploadAsset, rel.TagName, asset.Name)
+		if opts.DryRun {
+			continue
+		}
+		if err := mirrorAsset(source, target, *current, asset, opts.WorkDir); err != nil {
+			return fmt.Errorf("mirror %s asset %q: %w", rel.TagName, asset.Name, err)
+		}
+	}
+
+	if existed && uploads == 0 {
+		plan.add(MirrorUpToDate, rel.TagName, "")
+	}
+	return nil
+}
+
+// checkCreateRelease reports whether CreateRelease could send spec to r without
+// sending anything. The error wraps errors.ErrUnsupported when the provider
+// cannot represent the release.
+func checkCreateRelease(r ReleaseToFetch, spec ReleaseSpec) error {
+	w, err := newReleaseWriter(r)
+	if err != nil {
+		return err
+	}
+	_, _, err = w.writer.CreateReleaseRequest(w.baseURL, w.user, w.repo, providers.ReleaseSpec(spec))
+	return err
+}
+
+// mirrorAsset downloads asset from source into workDir and uploads it to release on target.
+func mirrorAsset(source, target ReleaseToFetch, release Release, asset Asset, workDir string) error {
+	dir, err := os.MkdirTemp(workDir, "gitearelease-mirror-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	file, err := DownloadReleaseAsset(source, asset, dir, "asset")
+	if err != nil {
+		return err
+	}
+
+	upload := AssetUpload{Path: file, Name: asset.Name, ContentType: asset.ContentType}
+	_, err = UploadAsset(target, release, upload)
+	return err
+}
+
+// fetchAllReleases returns every release of the repository selected by r, following pagination.
+func fetchAllReleases(r ReleaseToFetch) ([]Release, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	user, repo := repoCoordinates(r, providerType)
+	headers := requestHeaders(provider, r.Token)
+	pages, err := fetchAllPages(withPageSize(provider.GetReleasesURL(baseURL, user, repo, false), providerType), headers)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeReleasePages(provider, pages)
+}
+
+// normalizeReleasePages converts the pages of a release listing.
+func normalizeReleasePages(provider providers.Provider, pages [][]byte) ([]Release, error) {
+	var releases []Release
+	for _, apiData := range pages {
+		providerReleases, err := provider.NormalizeRelease(apiData, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range providerReleases {
+			releases = append(releases, convertProviderRelease(pr))
+		}
+	}
+	return releases, nil
+}
+
+// matchesTag reports whether tag passes the Tags and ExcludeTags patterns.
+func (o MirrorOptions) matchesTag(tag string) bool {
+	for _, pattern := range o.ExcludeTags {
+		if ok, _ := path.Match(pattern, tag); ok {
+			return false
+		}
+	}
+	if len(o.Tags) == 0 {
+		return true
+	}
+	for _, pattern := range o.Tags {
+		if ok, _ := path.Match(pattern, tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MirrorPlan) add(kind MirrorActionKind, tag, asset string) {
+	p.Actions = append(p.Actions, MirrorAction{Kind: kind, Tag: tag, Asset: asset})
+}
+
+// String renders the plan one step per line, for dry-run output.
+func (p MirrorPlan) String() string {
+	var b strings.Builder
+	for _, a := range p.Actions {
+		if a.Reason != "" {
+			fmt.Fprintf(&b, "%-14s %s (%s)\n", a.Kind, a.Tag, a.Reason)
+		} else if a.Asset != "" {
+			fmt.Fprintf(&b, "%-14s %s %s\n", a.Kind, a.Tag, a.Asset)
+		} else {
+			fmt.Fprintf(&b, "%-14s %s\n", a.Kind, a.Tag)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package gitearelease
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newFakeGiteaTarget returns a Gitea server that stores created releases and uploaded assets in memory.
+func newFakeGiteaTarget(t *testing.T) (*httptest.Server, *[]Release) {
+	t.Helper()
+	var mu sync.Mutex
+	releases := []Release{{ID: 1, TagName: "v1.0.0", Assets: []Asset{{ID: 10, Name: "tool-v1"}}}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		const prefix = "/api/v1/repos/mirror/tool/releases"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == prefix:
+			json.NewEncoder(w).Encode(releases)
+		case r.Method == http.MethodPost && r.URL.Path == prefix:
+			var spec map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&spec)
+			rel := Release{ID: len(releases) + 1, TagName: spec["tag_name"].(string), Name: spec["name"].(string)}
+			releases = append([]Release{rel}, releases...)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(rel)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/assets"):
+			var id int
+			fmt.Sscanf(strings.TrimPrefix(r.URL.Path, prefix+"/"), "%d/assets", &id)
+			file, _, err := r.FormFile("attachment")
+			if err != nil {
+				t.Fatalf("Expected attachment, got %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			name := r.URL.Query().Get("name")
+			if string(content) != "bin:"+name {
+				t.Errorf("Expected content of %s, got %q", name, content)
+			}
+			for i := range releases {
+				if releases[i].ID == id {
+					releases[i].Assets = append(releases[i].Assets, Asset{ID: 100 + len(releases[i].Assets), Name: name})
+				}
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 100, "name": "` + name + `"}`))
+		default:
+			t.Errorf("Unexpected target request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &releases
+}
+
+// newFakeGitHubSource returns a GitHub server with three releases, one of them a draft.
+func newFakeGitHubSource(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/up/tool/releases":
+			w.Write([]byte(`[
+				{"id": 4, "tag_name": "v3.0.0", "name": "v3", "draft": true, "assets": []},
+				{"id": 3, "tag_name": "v2.0.0", "name": "v2", "assets": [{"id": 31, "name": "tool-v2"}]},
+				{"id": 2, "tag_name": "v1.0.0", "name": "v1", "assets": [{"id": 21, "name": "tool-v1"}, {"id": 22, "name": "tool-v1.sha256"}]}
+			]`))
+		case "/repos/up/tool/releases/assets/31":
+			w.Write([]byte("bin:tool-v2"))
+		case "/repos/up/tool/releases/assets/22":
+			w.Write([]byte("bin:tool-v1.sha256"))
+		default:
+			t.Errorf("Unexpected source request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSyncReleases(t *testing.T) {
+	sourceServer := newFakeGitHubSource(t)
+	defer sourceServer.Close()
+	targetServer, targetReleases := newFakeGiteaTarget(t)
+	defer targetServer.Close()
+
+	source := ReleaseToFetch{BaseURL: sourceServer.URL, User: "up", Repo: "tool", Provider: "github"}
+	target := ReleaseToFetch{BaseURL: targetServer.URL, User: "mirror", Repo: "tool", Provider: "gitea", Token: "secret"}
+
+	plan, err := SyncReleases(source, target, MirrorOptions{DryRun: true, WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []MirrorAction{
+		{Kind: MirrorUploadAsset, Tag: "v1.0.0", Asset: "tool-v1.sha256"},
+		{Kind: MirrorCreateRelease, Tag: "v2.0.0"},
+		{Kind: MirrorUploadAsset, Tag: "v2.0.0", Asset: "tool-v2"},
+	}
+	if fmt.Sprint(plan.Actions) != fmt.Sprint(expected) {
+		t.Fatalf("Expected plan %v, got %v", expected, plan.Actions)
+	}
+	if len(*targetReleases) != 1 {
+		t.Fatalf("Expected dry run to leave the target alone, got %d releases", len(*targetReleases))
+	}
+
+	if _, err := SyncReleases(source, target, MirrorOptions{WorkDir: t.TempDir()}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(*targetReleases) != 2 || (*targetReleases)[0].TagName != "v2.0.0" || len((*targetReleases)[0].Assets) != 1 {
+		t.Fatalf("Expected v2.0.0 mirrored with its asset, got %+v", *targetReleases)
+	}
+
+	plan, err = SyncReleases(source, target, MirrorOptions{WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, a := range plan.Actions {
+		if a.Kind != MirrorUpToDate {
+			t.Errorf("Expected a second sync to change nothing, got %v", plan.Actions)
+			break
+		}
+	}
+}
+
+func TestSyncReleases_GitLabTarget(t *testing.T) {
+	sourceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": 4, "tag_name": "v3.0.0", "name": "v3", "draft": true, "assets": []},
+			{"id": 3, "tag_name": "v2.0.0-rc.1", "name": "v2 rc", "prerelease": true, "assets": []},
+			{"id": 2, "tag_name": "v1.1.0", "name": "v1.1", "prerelease": true, "assets": []},
+			{"id": 1, "tag_name": "v1.0.0", "name": "v1", "assets": []}
+		]`))
+	}))
+	defer sourceServer.Close()
+
+	var created []string
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/mirror%2Ftool/releases" {
+			t.Errorf("Unexpected target request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var spec map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&spec)
+		tag := spec["tag_name"].(string)
+		created = append(created, tag)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"tag_name": "` + tag + `", "name": "` + spec["name"].(string) + `"}`))
+	}))
+	defer targetServer.Close()
+
+	source := ReleaseToFetch{BaseURL: sourceServer.URL, User: "up", Repo: "tool", Provider: "github"}
+	target := ReleaseToFetch{BaseURL: targetServer.URL + "/api/v4", Project: "mirror/tool", Provider: "gitlab", Token: "secret"}
+
+	plan, err := SyncReleases(source, target, MirrorOptions{IncludeDrafts: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var kinds []string
+	for _, a := range plan.Actions {
+		kinds = append(kinds, a.Tag+":"+string(a.Kind))
+		if a.Kind == MirrorSkipped && a.Reason == "" {
+			t.Errorf("Expected a reason for skipping %s", a.Tag)
+		}
+	}
+	expected := "[v1.0.0:create-release v1.1.0:skipped v2.0.0-rc.1:create-release v3.0.0:skipped]"
+	if fmt.Sprint(kinds) != expected {
+		t.Errorf("Expected plan %s, got %v", expected, kinds)
+	}
+	if fmt.Sprint(created) != "[v1.0.0 v2.0.0-rc.1]" {
+		t.Errorf("Expected v1.0.0 and v2.0.0-rc.1 created, got %v", created)
+	}
+}
+
+func TestMirrorOptions_MatchesTag(t *testing.T) {
+	opts := MirrorOptions{Tags: []string{"v1.*", "v2.*"}, ExcludeTags: []string{"*-rc*"}}
+	tests := map[string]bool{
+		"v1.4.2":     true,
+		"v2.0.0":     true,
+		"v2.0.0-rc1": false,
+		"v3.0.0":     false,
+	}
+	for tag, expected := range tests {
+		if got := opts.matchesTag(tag); got != expected {
+			t.Errorf("matchesTag(%q) = %v, want %v", tag, got, expected)
+		}
+	}
+}
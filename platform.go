@@ -0,0 +1,89 @@
+package gitearelease
+
+import (
+	"strings"
+	"unicode"
+)
+
+// platformAlias maps a name used in release asset file names to a GOOS or GOARCH.
+type platformAlias struct {
+	alias string
+	value string
+}
+
+// osAliases lists the operating system names release tooling commonly uses.
+var osAliases = []platformAlias{
+	{"linux", "linux"},
+	{"darwin", "darwin"}, {"macos", "darwin"}, {"osx", "darwin"}, {"apple", "darwin"},
+	{"windows", "windows"}, {"win64", "windows"}, {"win32", "windows"},
+	{"freebsd", "freebsd"}, {"openbsd", "openbsd"}, {"netbsd", "netbsd"},
+	{"android", "android"},
+}
+
+// archAliases lists the architecture names release tooling commonly uses.
+// assetTokens rejoins "x86_64" and "x86-64", which tokenizing splits in two.
+var archAliases = []platformAlias{
+	{"x86_64", "amd64"}, {"amd64", "amd64"}, {"x64", "amd64"},
+	{"aarch64", "arm64"}, {"arm64", "arm64"},
+	{"armv7", "arm"}, {"armv6", "arm"}, {"armhf", "arm"}, {"arm", "arm"},
+	{"i386", "386"}, {"i686", "386"}, {"386", "386"}, {"x86", "386"},
+	{"riscv64", "riscv64"}, {"ppc64le", "ppc64le"}, {"s390x", "s390x"},
+	{"universal", "all"},
+}
+
+// AssetPlatform classifies a release asset by its file name, returning the
+// GOOS and GOARCH it was built for, such as "linux" and "arm64" for
+// "tool_1.2.0_Linux_aarch64.tar.gz". Only whole words between separators such
+// as "_", "-" and "." count, so "swarm" is not read as "arm". Either result is empty when
+// the name does not tell; both are empty for checksums, signatures and other
+// metadata files. A macOS universal binary has GOARCH "all".
+func AssetPlatform(name string) (goos, goarch string) {
+	name = strings.ToLower(name)
+	if isMetadataAsset(name) {
+		return "", ""
+	}
+	tokens := assetTokens(name)
+	goos = matchAlias(tokens, osAliases)
+	if goos == "" && strings.HasSuffix(name, ".exe") {
+		goos = "windows"
+	}
+	return goos, matchAlias(tokens, archAliases)
+}
+
+// assetTokens splits a lower-case asset name into its runs of letters and
+// digits, joining "x86" followed by "64" back into "x86_64".
+func assetTokens(name string) map[string]bool {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(words))
+	for i := 0; i < len(words); i++ {
+		if words[i] == "x86" && i+1 < len(words) && words[i+1] == "64" {
+			tokens["x86_64"] = true
+			i++
+			continue
+		}
+		tokens[words[i]] = true
+	}
+	return tokens
+}
+
+// matchAlias returns the value of the first alias that is one of tokens, or "".
+func matchAlias(tokens map[string]bool, aliases []platformAlias) string {
+	for _, a := range aliases {
+		if tokens[a.alias] {
+			return a.value
+		}
+	}
+	return ""
+}
+
+// isMetadataAsset reports whether a lower-case asset name is a checksum, signature or SBOM file.
+func isMetadataAsset(name string) bool {
+	for _, suffix := range []string{".sha256", ".sha512", ".sig", ".asc", ".pem", ".sbom", ".json", ".txt"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return strings.Contains(name, "checksums") || strings.Contains(name, "sha256sums")
+}
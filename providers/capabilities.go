@@ -0,0 +1,76 @@
+package providers
+
+import "strings"
+
+// Capabilities is a set of things a provider reports. A field whose
+// capability is missing holds a zero value that means "unknown", not zero.
+type Capabilities uint32
+
+// Capabilities a provider may report.
+const (
+	CapLatestRelease      Capabilities = 1 << iota // Native latest-release endpoint
+	CapReleaseCount                                // Accurate Repository.ReleaseCounter
+	CapDrafts                                      // Release.Draft set by the publisher
+	CapPrereleases                                 // Release.Prerelease set by the publisher, not inferred from the tag
+	CapTargetCommitish                             // Release.TargetCommitish
+	CapReleaseCommitSHA                            // Release.CommitSHA in release listings
+	CapAssetID                                     // Asset.ID usable in API requests
+	CapAssetSize                                   // Asset.Size
+	CapAssetDownloadCount                          // Asset.DownloadCount
+	CapAssetCreatedAt                              // Asset.CreatedAt
+	CapAssetContentType                            // Asset.ContentType
+	CapAssetUUID                                   // Asset.UUID
+)
+
+// AssetCapabilities are the capabilities that describe Asset fields, the
+// ones Asset.Known holds.
+const AssetCapabilities = CapAssetID | CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetContentType | CapAssetUUID
+
+// capabilityNames are the names of the capabilities, in bit order.
+var capabilityNames = []string{
+	"latest_release_endpoint",
+	"release_count",
+	"drafts",
+	"prereleases",
+	"target_commitish",
+	"release_commit_sha",
+	"asset_id",
+	"asset_size",
+	"asset_download_count",
+	"asset_created_at",
+	"asset_content_type",
+	"asset_uuid",
+}
+
+// Has reports whether c includes every capability in want.
+func (c Capabilities) Has(want Capabilities) bool {
+	return c&want == want
+}
+
+// Names returns the names of the capabilities in c, such as "asset_size".
+func (c Capabilities) Names() []string {
+	names := []string{}
+	for i, name := range capabilityNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (c Capabilities) String() string {
+	return strings.Join(c.Names(), ",")
+}
+
+// ParseCapabilities returns the capabilities named in names; unknown names are ignored.
+func ParseCapabilities(names []string) Capabilities {
+	var c Capabilities
+	for _, name := range names {
+		for i, known := range capabilityNames {
+			if name == known {
+				c |= 1 << i
+			}
+		}
+	}
+	return c
+}
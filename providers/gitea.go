@@ -3,6 +3,8 @@ package providers
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -23,29 +25,87 @@ func (p *GiteaProvider) GetReleasesURL(baseURL, user, repo string, latest bool)
 	return fmt.Sprintf("%s/api/v1/repos/%s/%s/%s", baseURL, user, repo, releaseType)
 }
 
+// GetReleaseByTagURL constructs the Gitea API URL for the release of a tag
+func (p *GiteaProvider) GetReleaseByTagURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/releases/tags/%s", baseURL, user, repo, url.PathEscape(tag))
+}
+
+// GetReleaseByIDURL constructs the Gitea API URL for a release by ID
+func (p *GiteaProvider) GetReleaseByIDURL(baseURL, user, repo string, id int) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/releases/%d", baseURL, user, repo, id)
+}
+
 // GetRepositoriesURL constructs the Gitea API URL for fetching repositories
 func (p *GiteaProvider) GetRepositoriesURL(baseURL, user string) string {
 	return fmt.Sprintf("%s/api/v1/users/%s/repos", baseURL, user)
 }
 
+// GetOrgRepositoriesURL constructs the Gitea API URL for fetching an organization's repositories
+func (p *GiteaProvider) GetOrgRepositoriesURL(baseURL, org string) string {
+	return fmt.Sprintf("%s/api/v1/orgs/%s/repos", baseURL, org)
+}
+
+// GetAuthenticatedRepositoriesURL constructs the Gitea API URL for fetching the token owner's repositories
+func (p *GiteaProvider) GetAuthenticatedRepositoriesURL(baseURL string) string {
+	return fmt.Sprintf("%s/api/v1/user/repos", baseURL)
+}
+
+// GetSearchRepositoriesURL constructs the Gitea API URL for searching repositories.
+// Gitea matches q against topics only when topic=true, so a Topic takes the place of
+// Query on the server and callers filter the query and language client-side.
+func (p *GiteaProvider) GetSearchRepositoriesURL(baseURL string, opts SearchOptions) string {
+	q := url.Values{}
+	if opts.Topic != "" {
+		q.Set("q", opts.Topic)
+		q.Set("topic", "true")
+	} else if opts.Query != "" {
+		q.Set("q", opts.Query)
+		q.Set("includeDesc", "true")
+	}
+	sorts := map[string]string{"name": "alpha", "stars": "stars", "forks": "forks", "created": "created", "updated": "updated"}
+	if sort, ok := sorts[opts.Sort]; ok {
+		q.Set("sort", sort)
+	}
+	if opts.Order == "asc" || opts.Order == "desc" {
+		q.Set("order", opts.Order)
+	}
+	return fmt.Sprintf("%s/api/v1/repos/search?%s", baseURL, q.Encode())
+}
+
+// NormalizeSearchRepositories converts a Gitea search response ({"ok": true, "data": [...]})
+func (p *GiteaProvider) NormalizeSearchRepositories(data []byte) ([]Repository, error) {
+	var resp struct {
+		OK   bool            `json:"ok"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("search repositories: server reported failure")
+	}
+	return p.NormalizeRepositories(resp.Data)
+}
+
 // NormalizeRelease converts Gitea JSON to the standard Release struct
 func (p *GiteaProvider) NormalizeRelease(data []byte, latest bool) ([]Release, error) {
 	// Gitea JSON matches our Release structure, but we need to handle the conversion
 	type giteaRelease struct {
-		ID          int     `json:"id"`
-		TagName     string  `json:"tag_name"`
-		Name        string  `json:"name"`
-		Body        string  `json:"body"`
-		URL         string  `json:"url"`
-		HTMLUrl     string  `json:"html_url"`
-		TarballURL  string  `json:"tarball_url"`
-		ZipballURL  string  `json:"zipball_url"`
-		Draft       bool    `json:"draft"`
-		Prerelease  bool    `json:"prerelease"`
-		CreatedAt   string  `json:"created_at"`
-		PublishedAt string  `json:"published_at"`
-		Author      Author  `json:"author"`
-		Assets      []Asset `json:"assets"`
+		ID          int          `json:"id"`
+		TagName     string       `json:"tag_name"`
+		Name        string       `json:"name"`
+		Body        string       `json:"body"`
+		URL         string       `json:"url"`
+		HTMLUrl     string       `json:"html_url"`
+		TarballURL  string       `json:"tarball_url"`
+		ZipballURL  string       `json:"zipball_url"`
+		Draft       bool         `json:"draft"`
+		Prerelease  bool         `json:"prerelease"`
+		CreatedAt   string       `json:"created_at"`
+		PublishedAt string       `json:"published_at"`
+		Author      Author       `json:"author"`
+		Assets      []giteaAsset `json:"assets"`
+		Target      string       `json:"target_commitish"`
 	}
 
 	var releases []Release
@@ -59,7 +119,7 @@ func (p *GiteaProvider) NormalizeRelease(data []byte, latest bool) ([]Release, e
 			ID:          giteaRel.ID,
 			TagName:     giteaRel.TagName,
 			Name:        giteaRel.Name,
-			Body:        strings.ReplaceAll(giteaRel.Body, "\n", " "),
+			Body:        giteaRel.Body,
 			URL:         giteaRel.URL,
 			HTMLUrl:     giteaRel.HTMLUrl,
 			TarballURL:  giteaRel.TarballURL,
@@ -69,7 +129,10 @@ func (p *GiteaProvider) NormalizeRelease(data []byte, latest bool) ([]Release, e
 			CreatedAt:   giteaRel.CreatedAt,
 			PublishedAt: giteaRel.PublishedAt,
 			Author:      giteaRel.Author,
-			Assets:      giteaRel.Assets,
+			Assets:      giteaAssets(giteaRel.Assets),
+
+			TargetCommitish: giteaRel.Target,
+			Capabilities:    p.Capabilities(),
 		}
 		releases = append(releases, rel)
 		return releases, nil
@@ -85,7 +148,7 @@ func (p *GiteaProvider) NormalizeRelease(data []byte, latest bool) ([]Release, e
 			ID:          giteaRel.ID,
 			TagName:     giteaRel.TagName,
 			Name:        giteaRel.Name,
-			Body:        strings.ReplaceAll(giteaRel.Body, "\n", " "),
+			Body:        giteaRel.Body,
 			URL:         giteaRel.URL,
 			HTMLUrl:     giteaRel.HTMLUrl,
 			TarballURL:  giteaRel.TarballURL,
@@ -95,7 +158,10 @@ func (p *GiteaProvider) NormalizeRelease(data []byte, latest bool) ([]Release, e
 			CreatedAt:   giteaRel.CreatedAt,
 			PublishedAt: giteaRel.PublishedAt,
 			Author:      giteaRel.Author,
-			Assets:      giteaRel.Assets,
+			Assets:      giteaAssets(giteaRel.Assets),
+
+			TargetCommitish: giteaRel.Target,
+			Capabilities:    p.Capabilities(),
 		}
 		releases = append(releases, rel)
 	}
@@ -114,6 +180,7 @@ func (p *GiteaProvider) NormalizeRepositories(data []byte) ([]Repository, error)
 		FullName        string `json:"full_name"`
 		Description     string `json:"description"`
 		Private         bool   `json:"private"`
+		Internal        bool   `json:"internal"`
 		Fork            bool   `json:"fork"`
 		Size            int    `json:"size"`
 		Language        string `json:"language"`
@@ -142,11 +209,12 @@ func (p *GiteaProvider) NormalizeRepositories(data []byte) ([]Repository, error)
 			Push  bool `json:"push"`
 			Pull  bool `json:"pull"`
 		} `json:"permissions"`
-		HasIssues   bool `json:"has_issues"`
-		HasWiki     bool `json:"has_wiki"`
-		HasProjects bool `json:"has_projects"`
-		HasReleases bool `json:"has_releases"`
-		HasPackages bool `json:"has_packages"`
+		HasIssues   bool     `json:"has_issues"`
+		HasWiki     bool     `json:"has_wiki"`
+		HasProjects bool     `json:"has_projects"`
+		HasReleases bool     `json:"has_releases"`
+		HasPackages bool     `json:"has_packages"`
+		Topics      []string `json:"topics"`
 	}
 
 	var giteaRepos []giteaRepo
@@ -161,6 +229,7 @@ func (p *GiteaProvider) NormalizeRepositories(data []byte) ([]Repository, error)
 			FullName:        gr.FullName,
 			Description:     gr.Description,
 			Private:         gr.Private,
+			Internal:        gr.Internal,
 			Fork:            gr.Fork,
 			Size:            gr.Size,
 			Language:        gr.Language,
@@ -194,6 +263,9 @@ func (p *GiteaProvider) NormalizeRepositories(data []byte) ([]Repository, error)
 			HasProjects: gr.HasProjects,
 			HasReleases: gr.HasReleases,
 			HasPackages: gr.HasPackages,
+			Topics:      gr.Topics,
+
+			Capabilities: p.Capabilities(),
 		})
 	}
 
@@ -208,5 +280,168 @@ func (p *GiteaProvider) DetectProvider(baseURL string) bool {
 	return !strings.Contains(lowerURL, "github.com") &&
 		!strings.Contains(lowerURL, "gitlab.com") &&
 		!strings.Contains(lowerURL, "api.github.com") &&
+		!strings.Contains(lowerURL, "/api/v3") &&
 		!strings.Contains(lowerURL, "gitlab")
 }
+
+// AuthHeader returns the Gitea token authorization header
+func (p *GiteaProvider) AuthHeader(token string) (string, string) {
+	return "Authorization", "token " + token
+}
+
+// Capabilities returns what Gitea reports: everything but asset content types
+func (p *GiteaProvider) Capabilities() Capabilities {
+	return CapLatestRelease | CapReleaseCount | CapDrafts | CapPrereleases | CapTargetCommitish |
+		CapAssetID | CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetUUID
+}
+
+// CreateReleaseRequest returns the Gitea API URL and payload that create a release
+func (p *GiteaProvider) CreateReleaseRequest(baseURL, user, repo string, spec ReleaseSpec) (string, interface{}, error) {
+	return p.GetReleasesURL(baseURL, user, repo, false), newReleasePayload(spec), nil
+}
+
+// UpdateReleaseRequest returns the Gitea API request that edits a release
+func (p *GiteaProvider) UpdateReleaseRequest(baseURL, user, repo string, current Release, spec ReleaseSpec) (string, string, interface{}, error) {
+	return http.MethodPatch, p.GetReleaseByIDURL(baseURL, user, repo, current.ID), newReleasePayload(spec), nil
+}
+
+// DeleteReleaseURL returns the Gitea API URL that deletes a release
+func (p *GiteaProvider) DeleteReleaseURL(baseURL, user, repo string, current Release) string {
+	return p.GetReleaseByIDURL(baseURL, user, repo, current.ID)
+}
+
+type giteaAsset struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	CreatedAt          string `json:"created_at"`
+	UUID               string `json:"uuid"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (a giteaAsset) toAsset() Asset {
+	return Asset{
+		ID:                 a.ID,
+		Name:               a.Name,
+		Size:               a.Size,
+		DownloadCount:      a.DownloadCount,
+		CreatedAt:          a.CreatedAt,
+		UUID:               a.UUID,
+		BrowserDownloadURL: a.BrowserDownloadURL,
+		Known:              CapAssetID | CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetUUID,
+	}
+}
+
+// giteaAssets converts the attachments of a Gitea release
+func giteaAssets(attachments []giteaAsset) []Asset {
+	assets := make([]Asset, len(attachments))
+	for i, a := range attachments {
+		assets[i] = a.toAsset()
+	}
+	return assets
+}
+
+// UploadAssetRequest returns the Gitea API request that attaches a file to release
+func (p *GiteaProvider) UploadAssetRequest(baseURL, user, repo string, release Release, name string) AssetUploadRequest {
+	return AssetUploadRequest{
+		Method:    http.MethodPost,
+		URL:       fmt.Sprintf("%s/assets?name=%s", p.GetReleaseByIDURL(baseURL, user, repo, release.ID), url.QueryEscape(name)),
+		FormField: "attachment",
+	}
+}
+
+// LinkAssetRequest returns "" because the Gitea upload already attaches the asset
+func (p *GiteaProvider) LinkAssetRequest(baseURL, user, repo string, release Release, name, uploadURL string) (string, interface{}) {
+	return "", nil
+}
+
+// NormalizeAsset converts a Gitea attachment JSON object to the standard Asset struct
+func (p *GiteaProvider) NormalizeAsset(data []byte) (Asset, error) {
+	var attachment giteaAsset
+	if err := json.Unmarshal(data, &attachment); err != nil {
+		return Asset{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	return attachment.toAsset(), nil
+}
+
+// DeleteAssetURL returns the Gitea API URL that deletes asset
+func (p *GiteaProvider) DeleteAssetURL(baseURL, user, repo string, release Release, asset Asset) string {
+	return fmt.Sprintf("%s/assets/%d", p.GetReleaseByIDURL(baseURL, user, repo, release.ID), asset.ID)
+}
+
+// GetTagsURL constructs the Gitea API URL for fetching tags
+func (p *GiteaProvider) GetTagsURL(baseURL, user, repo string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/tags", baseURL, user, repo)
+}
+
+// NormalizeTags converts Gitea tag JSON to the standard Tag slice
+func (p *GiteaProvider) NormalizeTags(data []byte) ([]Tag, error) {
+	var giteaTags []struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+		Commit  struct {
+			SHA     string `json:"sha"`
+			Created string `json:"created"`
+		} `json:"commit"`
+		TarballURL string `json:"tarball_url"`
+		ZipballURL string `json:"zipball_url"`
+	}
+	if err := json.Unmarshal(data, &giteaTags); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	tags := make([]Tag, len(giteaTags))
+	for i, gt := range giteaTags {
+		tags[i] = Tag{
+			Name:       gt.Name,
+			CommitSHA:  gt.Commit.SHA,
+			Date:       gt.Commit.Created,
+			Message:    strings.TrimSpace(gt.Message),
+			TarballURL: gt.TarballURL,
+			ZipballURL: gt.ZipballURL,
+		}
+	}
+	return tags, nil
+}
+
+// GetTagCommitURL constructs the Gitea API URL for a single tag
+func (p *GiteaProvider) GetTagCommitURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/tags/%s", baseURL, user, repo, url.PathEscape(tag))
+}
+
+// NormalizeTagCommit extracts the commit SHA from a Gitea tag
+func (p *GiteaProvider) NormalizeTagCommit(data []byte) (string, error) {
+	var tag struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return "", fmt.Errorf("parse JSON: %w", err)
+	}
+	return tag.Commit.SHA, nil
+}
+
+// WebhookEvent returns the X-Gitea-Event header
+func (p *GiteaProvider) WebhookEvent(h http.Header) string {
+	return h.Get("X-Gitea-Event")
+}
+
+// VerifyWebhook checks the X-Gitea-Signature HMAC-SHA256 of body
+func (p *GiteaProvider) VerifyWebhook(h http.Header, body []byte, secret string) bool {
+	return validHMAC(h.Get("X-Gitea-Signature"), body, secret)
+}
+
+// NormalizeReleaseWebhook decodes a Gitea "release" event payload
+func (p *GiteaProvider) NormalizeReleaseWebhook(event string, data []byte) (WebhookRelease, bool, error) {
+	if event != "release" {
+		return WebhookRelease{}, false, nil
+	}
+	hook, err := normalizeReleaseWebhook(p, data, map[string]WebhookAction{
+		"published": WebhookPublished,
+		"updated":   WebhookUpdated,
+		"deleted":   WebhookDeleted,
+	})
+	return hook, err == nil, err
+}
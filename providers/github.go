@@ -3,11 +3,16 @@ package providers
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
-	"time"
 )
 
-// GitHubProvider implements the Provider interface for GitHub
+// githubUploadsURL is the upload host used by github.com; GitHub Enterprise
+// Server serves uploads from /api/uploads on the instance itself.
+const githubUploadsURL = "https://uploads.github.com"
+
+// GitHubProvider implements the Provider interface for GitHub and GitHub Enterprise Server
 type GitHubProvider struct{}
 
 // NewGitHubProvider creates a new GitHub provider instance
@@ -23,11 +28,98 @@ func (p *GitHubProvider) GetReleasesURL(baseURL, user, repo string, latest bool)
 	return fmt.Sprintf("%s/repos/%s/%s/releases", baseURL, user, repo)
 }
 
+// GetReleaseByTagURL constructs the GitHub API URL for the release of a tag
+func (p *GitHubProvider) GetReleaseByTagURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", baseURL, user, repo, url.PathEscape(tag))
+}
+
+// GetReleaseByIDURL constructs the GitHub API URL for a release by ID
+func (p *GitHubProvider) GetReleaseByIDURL(baseURL, user, repo string, id int) string {
+	return fmt.Sprintf("%s/repos/%s/%s/releases/%d", baseURL, user, repo, id)
+}
+
 // GetRepositoriesURL constructs the GitHub API URL for fetching repositories
 func (p *GitHubProvider) GetRepositoriesURL(baseURL, user string) string {
 	return fmt.Sprintf("%s/users/%s/repos", baseURL, user)
 }
 
+// GetOrgRepositoriesURL constructs the GitHub API URL for fetching an organization's repositories
+func (p *GitHubProvider) GetOrgRepositoriesURL(baseURL, org string) string {
+	return fmt.Sprintf("%s/orgs/%s/repos", baseURL, org)
+}
+
+// GetAuthenticatedRepositoriesURL constructs the GitHub API URL for fetching the token owner's repositories,
+// including private ones and those they collaborate on
+func (p *GitHubProvider) GetAuthenticatedRepositoriesURL(baseURL string) string {
+	return fmt.Sprintf("%s/user/repos", baseURL)
+}
+
+// GetSearchRepositoriesURL constructs the GitHub API URL for searching repositories.
+// Topic, Language and Owner become topic:, language: and user: qualifiers.
+func (p *GitHubProvider) GetSearchRepositoriesURL(baseURL string, opts SearchOptions) string {
+	terms := []string{}
+	if opts.Query != "" {
+		terms = append(terms, opts.Query)
+	}
+	if opts.Topic != "" {
+		terms = append(terms, "topic:"+opts.Topic)
+	}
+	if opts.Language != "" {
+		terms = append(terms, "language:"+opts.Language)
+	}
+	if opts.Owner != "" {
+		terms = append(terms, "user:"+opts.Owner)
+	}
+
+	q := url.Values{}
+	q.Set("q", strings.Join(terms, " "))
+	// GitHub sorts by best match unless one of these is given
+	sorts := map[string]string{"stars": "stars", "forks": "forks", "updated": "updated"}
+	if sort, ok := sorts[opts.Sort]; ok {
+		q.Set("sort", sort)
+	}
+	if opts.Order == "asc" || opts.Order == "desc" {
+		q.Set("order", opts.Order)
+	}
+	return fmt.Sprintf("%s/search/repositories?%s", baseURL, q.Encode())
+}
+
+// NormalizeSearchRepositories converts a GitHub search response ({"total_count": n, "items": [...]})
+func (p *GitHubProvider) NormalizeSearchRepositories(data []byte) ([]Repository, error) {
+	var resp struct {
+		Items json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	if resp.Items == nil {
+		return nil, nil
+	}
+	return p.NormalizeRepositories(resp.Items)
+}
+
+// GetAssetURL constructs the GitHub API URL of a single release asset.
+// Requesting it with Accept: application/octet-stream returns the binary.
+func (p *GitHubProvider) GetAssetURL(baseURL, user, repo string, assetID int) string {
+	return fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", baseURL, user, repo, assetID)
+}
+
+// GetUploadsURL returns the root of the upload API for baseURL.
+// github.com uses uploads.github.com, GitHub Enterprise Server uses /api/uploads.
+func (p *GitHubProvider) GetUploadsURL(baseURL string) string {
+	if p.IsEnterprise(baseURL) {
+		idx := strings.Index(strings.ToLower(baseURL), "/api/v3")
+		return baseURL[:idx] + "/api/uploads"
+	}
+	return githubUploadsURL
+}
+
+// IsEnterprise reports whether baseURL is a GitHub Enterprise Server API root (https://host/api/v3)
+func (p *GitHubProvider) IsEnterprise(baseURL string) bool {
+	lowerURL := strings.ToLower(baseURL)
+	return strings.Contains(lowerURL, "/api/v3") && !strings.Contains(lowerURL, "api.github.com")
+}
+
 // githubRelease represents GitHub's release JSON structure
 type githubRelease struct {
 	ID          int    `json:"id"`
@@ -42,20 +134,25 @@ type githubRelease struct {
 	Prerelease  bool   `json:"prerelease"`
 	CreatedAt   string `json:"created_at"`
 	PublishedAt string `json:"published_at"`
+	Target      string `json:"target_commitish"`
+	UploadURL   string `json:"upload_url"`
 	Author      struct {
 		Login string `json:"login"`
 		ID    int    `json:"id"`
 		Type  string `json:"type"`
 	} `json:"author"`
-	Assets []struct {
-		ID                 int    `json:"id"`
-		Name               string `json:"name"`
-		Size               int64  `json:"size"`
-		DownloadCount      int    `json:"download_count"`
-		CreatedAt          string `json:"created_at"`
-		BrowserDownloadURL string `json:"browser_download_url"`
-		ContentType        string `json:"content_type"`
-	} `json:"assets"`
+	Assets []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	ID                 int    `json:"id"`
+	URL                string `json:"url"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	CreatedAt          string `json:"created_at"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	ContentType        string `json:"content_type"`
 }
 
 // NormalizeRelease converts GitHub JSON to the standard Release struct
@@ -91,7 +188,7 @@ func (p *GitHubProvider) convertGitHubRelease(ghRel githubRelease) Release {
 		ID:          ghRel.ID,
 		TagName:     ghRel.TagName,
 		Name:        ghRel.Name,
-		Body:        strings.ReplaceAll(ghRel.Body, "\n", " "),
+		Body:        ghRel.Body,
 		URL:         ghRel.URL,
 		HTMLUrl:     ghRel.HTMLURL,
 		TarballURL:  ghRel.TarballURL,
@@ -105,18 +202,14 @@ func (p *GitHubProvider) convertGitHubRelease(ghRel githubRelease) Release {
 			Username: ghRel.Author.Login,
 		},
 		Assets: make([]Asset, len(ghRel.Assets)),
+
+		TargetCommitish: ghRel.Target,
+		UploadURL:       ghRel.UploadURL,
+		Capabilities:    p.Capabilities(),
 	}
 
 	for i, ghAsset := range ghRel.Assets {
-		rel.Assets[i] = Asset{
-			ID:                 ghAsset.ID,
-			Name:               ghAsset.Name,
-			Size:               ghAsset.Size,
-			DownloadCount:      ghAsset.DownloadCount,
-			CreatedAt:          ghAsset.CreatedAt,
-			BrowserDownloadURL: ghAsset.BrowserDownloadURL,
-			Type:               ghAsset.ContentType,
-		}
+		rel.Assets[i] = ghAsset.toAsset()
 	}
 
 	return rel
@@ -129,6 +222,7 @@ type githubRepository struct {
 	FullName        string `json:"full_name"`
 	Description     string `json:"description"`
 	Private         bool   `json:"private"`
+	Visibility      string `json:"visibility"`
 	Fork            bool   `json:"fork"`
 	Size            int    `json:"size"`
 	Language        string `json:"language"`
@@ -154,11 +248,12 @@ type githubRepository struct {
 		Push  bool `json:"push"`
 		Pull  bool `json:"pull"`
 	} `json:"permissions"`
-	HasIssues   bool `json:"has_issues"`
-	HasWiki     bool `json:"has_wiki"`
-	HasProjects bool `json:"has_projects"`
-	HasReleases bool `json:"has_releases"`
-	HasPackages bool `json:"has_packages"`
+	HasIssues   bool     `json:"has_issues"`
+	HasWiki     bool     `json:"has_wiki"`
+	HasProjects bool     `json:"has_projects"`
+	HasReleases bool     `json:"has_releases"`
+	HasPackages bool     `json:"has_packages"`
+	Topics      []string `json:"topics"`
 }
 
 // NormalizeRepositories converts GitHub JSON to the standard Repository slice
@@ -178,15 +273,13 @@ func (p *GitHubProvider) NormalizeRepositories(data []byte) ([]Repository, error
 
 // convertGitHubRepository converts a GitHub repository to the standard Repository struct
 func (p *GitHubProvider) convertGitHubRepository(ghRepo githubRepository) Repository {
-	createdAt, _ := time.Parse(time.RFC3339, ghRepo.CreatedAt)
-	updatedAt, _ := time.Parse(time.RFC3339, ghRepo.UpdatedAt)
-
 	repo := Repository{
 		ID:              ghRepo.ID,
 		Name:            ghRepo.Name,
 		FullName:        ghRepo.FullName,
 		Description:     ghRepo.Description,
 		Private:         ghRepo.Private,
+		Internal:        ghRepo.Visibility == "internal",
 		Fork:            ghRepo.Fork,
 		Size:            ghRepo.Size,
 		Language:        ghRepo.Language,
@@ -199,13 +292,15 @@ func (p *GitHubProvider) convertGitHubRepository(ghRepo githubRepository) Reposi
 		OpenIssuesCount: ghRepo.OpenIssuesCount,
 		DefaultBranch:   ghRepo.DefaultBranch,
 		Archived:        ghRepo.Archived,
-		CreatedAt:       createdAt.Format(time.RFC3339),
-		UpdatedAt:       updatedAt.Format(time.RFC3339),
+		CreatedAt:       ghRepo.CreatedAt,
+		UpdatedAt:       ghRepo.UpdatedAt,
 		HasIssues:       ghRepo.HasIssues,
 		HasWiki:         ghRepo.HasWiki,
 		HasProjects:     ghRepo.HasProjects,
 		HasReleases:     ghRepo.HasReleases,
 		HasPackages:     ghRepo.HasPackages,
+		Topics:          ghRepo.Topics,
+		Capabilities:    p.Capabilities(),
 	}
 
 	repo.Owner = Owner{
@@ -234,8 +329,159 @@ func (p *GitHubProvider) convertGitHubRepository(ghRepo githubRepository) Reposi
 	return repo
 }
 
-// DetectProvider checks if the baseURL is GitHub
+// DetectProvider checks if the baseURL is GitHub or a GitHub Enterprise Server API root
 func (p *GitHubProvider) DetectProvider(baseURL string) bool {
 	lowerURL := strings.ToLower(baseURL)
-	return strings.Contains(lowerURL, "github.com") || strings.Contains(lowerURL, "api.github.com")
+	return strings.Contains(lowerURL, "github.com") || strings.Contains(lowerURL, "api.github.com") || p.IsEnterprise(baseURL)
+}
+
+// AuthHeader returns the GitHub bearer token header
+func (p *GitHubProvider) AuthHeader(token string) (string, string) {
+	return "Authorization", "Bearer " + token
+}
+
+// Capabilities returns what the GitHub REST API reports. Repository release
+// counts need one extra request each (or GraphQL), and releases list only the
+// target branch, not the tagged commit
+func (p *GitHubProvider) Capabilities() Capabilities {
+	return CapLatestRelease | CapDrafts | CapPrereleases | CapTargetCommitish |
+		CapAssetID | CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetContentType
+}
+
+// CreateReleaseRequest returns the GitHub API URL and payload that create a release
+func (p *GitHubProvider) CreateReleaseRequest(baseURL, user, repo string, spec ReleaseSpec) (string, interface{}, error) {
+	return p.GetReleasesURL(baseURL, user, repo, false), newReleasePayload(spec), nil
+}
+
+// UpdateReleaseRequest returns the GitHub API request that edits a release
+func (p *GitHubProvider) UpdateReleaseRequest(baseURL, user, repo string, current Release, spec ReleaseSpec) (string, string, interface{}, error) {
+	return http.MethodPatch, p.GetReleaseByIDURL(baseURL, user, repo, current.ID), newReleasePayload(spec), nil
+}
+
+// DeleteReleaseURL returns the GitHub API URL that deletes a release
+func (p *GitHubProvider) DeleteReleaseURL(baseURL, user, repo string, current Release) string {
+	return p.GetReleaseByIDURL(baseURL, user, repo, current.ID)
+}
+
+func (a githubAsset) toAsset() Asset {
+	return Asset{
+		ID:                 a.ID,
+		Name:               a.Name,
+		URL:                a.URL,
+		Size:               a.Size,
+		DownloadCount:      a.DownloadCount,
+		CreatedAt:          a.CreatedAt,
+		BrowserDownloadURL: a.BrowserDownloadURL,
+		ContentType:        a.ContentType,
+		Known:              CapAssetID | CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetContentType,
+	}
+}
+
+// UploadAssetRequest returns the GitHub uploads host request that attaches a file to release.
+// The release's upload_url is used when known, so proxies and custom upload hosts work;
+// otherwise the URL is derived from baseURL.
+func (p *GitHubProvider) UploadAssetRequest(baseURL, user, repo string, release Release, name string) AssetUploadRequest {
+	uploadURL, _, _ := strings.Cut(release.UploadURL, "{")
+	if uploadURL == "" {
+		uploadURL = fmt.Sprintf("%s/repos/%s/%s/releases/%d/assets", p.GetUploadsURL(baseURL), user, repo, release.ID)
+	}
+	return AssetUploadRequest{
+		Method: http.MethodPost,
+		URL:    uploadURL + "?name=" + url.QueryEscape(name),
+	}
+}
+
+// LinkAssetRequest returns "" because the GitHub upload already attaches the asset
+func (p *GitHubProvider) LinkAssetRequest(baseURL, user, repo string, release Release, name, uploadURL string) (string, interface{}) {
+	return "", nil
+}
+
+// NormalizeAsset converts a GitHub asset JSON object to the standard Asset struct
+func (p *GitHubProvider) NormalizeAsset(data []byte) (Asset, error) {
+	var ghAsset githubAsset
+	if err := json.Unmarshal(data, &ghAsset); err != nil {
+		return Asset{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	return ghAsset.toAsset(), nil
+}
+
+// DeleteAssetURL returns the GitHub API URL that deletes asset
+func (p *GitHubProvider) DeleteAssetURL(baseURL, user, repo string, release Release, asset Asset) string {
+	return p.GetAssetURL(baseURL, user, repo, asset.ID)
+}
+
+// GetTagsURL constructs the GitHub API URL for fetching tags
+func (p *GitHubProvider) GetTagsURL(baseURL, user, repo string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/tags", baseURL, user, repo)
+}
+
+// NormalizeTags converts GitHub tag JSON to the standard Tag slice.
+// The tags endpoint reports neither a date nor an annotation message.
+func (p *GitHubProvider) NormalizeTags(data []byte) ([]Tag, error) {
+	var ghTags []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+		TarballURL string `json:"tarball_url"`
+		ZipballURL string `json:"zipball_url"`
+	}
+	if err := json.Unmarshal(data, &ghTags); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	tags := make([]Tag, len(ghTags))
+	for i, gt := range ghTags {
+		tags[i] = Tag{
+			Name:       gt.Name,
+			CommitSHA:  gt.Commit.SHA,
+			TarballURL: gt.TarballURL,
+			ZipballURL: gt.ZipballURL,
+		}
+	}
+	return tags, nil
+}
+
+// GetTagCommitURL constructs the GitHub API URL of the commit a tag points at.
+// The commits endpoint peels annotated tags, so one request is enough.
+func (p *GitHubProvider) GetTagCommitURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/commits/%s", baseURL, user, repo, url.PathEscape(tag))
+}
+
+// NormalizeTagCommit extracts the SHA from a GitHub commit
+func (p *GitHubProvider) NormalizeTagCommit(data []byte) (string, error) {
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal(data, &commit); err != nil {
+		return "", fmt.Errorf("parse JSON: %w", err)
+	}
+	return commit.SHA, nil
+}
+
+// WebhookEvent returns the X-GitHub-Event header
+func (p *GitHubProvider) WebhookEvent(h http.Header) string {
+	return h.Get("X-GitHub-Event")
+}
+
+// VerifyWebhook checks the X-Hub-Signature-256 HMAC-SHA256 of body
+func (p *GitHubProvider) VerifyWebhook(h http.Header, body []byte, secret string) bool {
+	signature, ok := strings.CutPrefix(h.Get("X-Hub-Signature-256"), "sha256=")
+	return ok && validHMAC(signature, body, secret)
+}
+
+// NormalizeReleaseWebhook decodes a GitHub "release" event payload. The
+// "released" and "prereleased" actions repeat "published" and have no Action.
+func (p *GitHubProvider) NormalizeReleaseWebhook(event string, data []byte) (WebhookRelease, bool, error) {
+	if event != "release" {
+		return WebhookRelease{}, false, nil
+	}
+	hook, err := normalizeReleaseWebhook(p, data, map[string]WebhookAction{
+		"created":     WebhookCreated,
+		"published":   WebhookPublished,
+		"edited":      WebhookUpdated,
+		"deleted":     WebhookDeleted,
+		"unpublished": WebhookUnpublished,
+	})
+	return hook, err == nil, err
 }
@@ -0,0 +1,338 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// githubRepositoriesSelection lists repositories together with their release
+// count and latest release, so a dashboard needs one request per 100
+// repositories instead of 1+N REST calls.
+const githubRepositoriesSelection = `repositories(first: 100, after: $cursor, orderBy: {field: NAME, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId name nameWithOwner description url sshUrl visibility
+        isPrivate isFork isArchived diskUsage createdAt updatedAt
+        hasIssuesEnabled hasWikiEnabled hasProjectsEnabled
+        stargazerCount forkCount viewerPermission
+        primaryLanguage { name }
+        defaultBranchRef { name }
+        watchers { totalCount }
+        issues(states: OPEN) { totalCount }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        owner { login avatarUrl ... on User { databaseId } ... on Organization { databaseId } }
+        releases { totalCount }
+        latestRelease {
+          databaseId tagName name description url isDraft isPrerelease createdAt publishedAt
+          author { login name email }
+          tagCommit { oid }
+          releaseAssets(first: 100) { nodes { name size downloadCount createdAt downloadUrl contentType } }
+        }
+      }
+    }`
+
+// githubOwnerRepositoriesQuery lists the repositories of a user or organization
+const githubOwnerRepositoriesQuery = `query($login: String!, $cursor: String) {
+  repositoryOwner(login: $login) {
+    ` + githubRepositoriesSelection + `
+  }
+}`
+
+// githubViewerRepositoriesQuery lists the repositories of the authenticated user
+const githubViewerRepositoriesQuery = `query($cursor: String) {
+  viewer {
+    ` + githubRepositoriesSelection + `
+  }
+}`
+
+// GraphQLRequest is the JSON body of a GraphQL call
+type GraphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLRepositoryPage is one page of repositories returned by the GraphQL API.
+// Latest holds the latest release of Repositories[i], or nil if it has none.
+type GraphQLRepositoryPage struct {
+	Repositories []Repository
+	Latest       []*Release
+	HasNextPage  bool
+	EndCursor    string
+}
+
+// GetGraphQLURL returns the GraphQL endpoint for baseURL.
+// github.com uses api.github.com/graphql, GitHub Enterprise Server uses /api/graphql.
+func (p *GitHubProvider) GetGraphQLURL(baseURL string) string {
+	if p.IsEnterprise(baseURL) {
+		idx := strings.Index(strings.ToLower(baseURL), "/api/v3")
+		return baseURL[:idx] + "/api/graphql"
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/graphql"
+}
+
+// RepositoriesGraphQLRequest returns the GraphQL request for one page of login's repositories,
+// or of the authenticated user's repositories if login is empty.
+// Pass the EndCursor of the previous page, or "" for the first page.
+func (p *GitHubProvider) RepositoriesGraphQLRequest(login, cursor string) GraphQLRequest {
+	vars := map[string]interface{}{}
+	if cursor != "" {
+		vars["cursor"] = cursor
+	}
+	if login == "" {
+		return GraphQLRequest{Query: githubViewerRepositoriesQuery, Variables: vars}
+	}
+	vars["login"] = login
+	return GraphQLRequest{Query: githubOwnerRepositoriesQuery, Variables: vars}
+}
+
+// githubGraphQLRelease represents a release node in GitHub's GraphQL schema
+type githubGraphQLRelease struct {
+	DatabaseID   int    `json:"databaseId"`
+	TagName      string `json:"tagName"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	URL          string `json:"url"`
+	IsDraft      bool   `json:"isDraft"`
+	IsPrerelease bool   `json:"isPrerelease"`
+	CreatedAt    string `json:"createdAt"`
+	PublishedAt  string `json:"publishedAt"`
+	Author       *struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+	TagCommit *struct {
+		OID string `json:"oid"`
+	} `json:"tagCommit"`
+	ReleaseAssets struct {
+		Nodes []struct {
+			Name          string `json:"name"`
+			Size          int64  `json:"size"`
+			DownloadCount int    `json:"downloadCount"`
+			CreatedAt     string `json:"createdAt"`
+			DownloadURL   string `json:"downloadUrl"`
+			ContentType   string `json:"contentType"`
+		} `json:"nodes"`
+	} `json:"releaseAssets"`
+}
+
+// githubGraphQLRepository represents a repository node in GitHub's GraphQL schema
+type githubGraphQLRepository struct {
+	DatabaseID         int    `json:"databaseId"`
+	Name               string `json:"name"`
+	NameWithOwner      string `json:"nameWithOwner"`
+	Description        string `json:"description"`
+	URL                string `json:"url"`
+	SSHURL             string `json:"sshUrl"`
+	Visibility         string `json:"visibility"`
+	IsPrivate          bool   `json:"isPrivate"`
+	IsFork             bool   `json:"isFork"`
+	IsArchived         bool   `json:"isArchived"`
+	DiskUsage          int    `json:"diskUsage"`
+	CreatedAt          string `json:"createdAt"`
+	UpdatedAt          string `json:"updatedAt"`
+	HasIssuesEnabled   bool   `json:"hasIssuesEnabled"`
+	HasWikiEnabled     bool   `json:"hasWikiEnabled"`
+	HasProjectsEnabled bool   `json:"hasProjectsEnabled"`
+	StargazerCount     int    `json:"stargazerCount"`
+	ForkCount          int    `json:"forkCount"`
+	ViewerPermission   string `json:"viewerPermission"`
+	PrimaryLanguage    *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	Watchers struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"watchers"`
+	Issues struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"issues"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	Owner struct {
+		Login      string `json:"login"`
+		AvatarURL  string `json:"avatarUrl"`
+		DatabaseID int    `json:"databaseId"`
+	} `json:"owner"`
+	Releases struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"releases"`
+	LatestRelease *githubGraphQLRelease `json:"latestRelease"`
+}
+
+// NormalizeGraphQLRepositories converts a GraphQL repositories response to the standard types
+func (p *GitHubProvider) NormalizeGraphQLRepositories(data []byte) (GraphQLRepositoryPage, error) {
+	type repositoryConnection struct {
+		Repositories struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []githubGraphQLRepository `json:"nodes"`
+		} `json:"repositories"`
+	}
+	var resp struct {
+		Data struct {
+			RepositoryOwner *repositoryConnection `json:"repositoryOwner"`
+			Viewer          *repositoryConnection `json:"viewer"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	var page GraphQLRepositoryPage
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return page, fmt.Errorf("parse JSON: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return page, fmt.Errorf("graphql: %s", strings.Join(messages, "; "))
+	}
+	owner := resp.Data.RepositoryOwner
+	if owner == nil {
+		owner = resp.Data.Viewer
+	}
+	if owner == nil {
+		return page, fmt.Errorf("graphql: owner not found")
+	}
+
+	repos := owner.Repositories
+	page.HasNextPage = repos.PageInfo.HasNextPage
+	page.EndCursor = repos.PageInfo.EndCursor
+	page.Repositories = make([]Repository, len(repos.Nodes))
+	page.Latest = make([]*Release, len(repos.Nodes))
+	for i, node := range repos.Nodes {
+		page.Repositories[i] = p.convertGraphQLRepository(node)
+		if node.LatestRelease != nil {
+			rel := p.convertGraphQLRelease(*node.LatestRelease, node.URL)
+			page.Latest[i] = &rel
+		}
+	}
+
+	return page, nil
+}
+
+// convertGraphQLRepository converts a GraphQL repository node to the standard Repository struct
+func (p *GitHubProvider) convertGraphQLRepository(node githubGraphQLRepository) Repository {
+	repo := Repository{
+		ID:              node.DatabaseID,
+		Name:            node.Name,
+		FullName:        node.NameWithOwner,
+		Description:     node.Description,
+		Private:         node.IsPrivate,
+		Internal:        node.Visibility == "INTERNAL",
+		Fork:            node.IsFork,
+		Size:            node.DiskUsage,
+		HTMLURL:         node.URL,
+		CloneURL:        node.URL + ".git",
+		SSHURL:          node.SSHURL,
+		StarsCount:      node.StargazerCount,
+		ForksCount:      node.ForkCount,
+		WatchersCount:   node.Watchers.TotalCount,
+		OpenIssuesCount: node.Issues.TotalCount,
+		ReleaseCounter:  node.Releases.TotalCount, // Accurate, unlike the REST placeholder
+		Archived:        node.IsArchived,
+		CreatedAt:       node.CreatedAt,
+		UpdatedAt:       node.UpdatedAt,
+		HasIssues:       node.HasIssuesEnabled,
+		HasWiki:         node.HasWikiEnabled,
+		HasProjects:     node.HasProjectsEnabled,
+		HasReleases:     node.Releases.TotalCount > 0,
+		Capabilities:    p.Capabilities() | CapReleaseCount,
+	}
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	if node.DefaultBranchRef != nil {
+		repo.DefaultBranch = node.DefaultBranchRef.Name
+	}
+	for _, t := range node.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, t.Topic.Name)
+	}
+
+	repo.Owner = Owner{
+		ID:        node.Owner.DatabaseID,
+		Login:     node.Owner.Login,
+		Username:  node.Owner.Login,
+		AvatarURL: node.Owner.AvatarURL,
+	}
+
+	// viewerPermission: ADMIN, MAINTAIN, WRITE, TRIAGE or READ
+	switch node.ViewerPermission {
+	case "ADMIN":
+		repo.Permissions = Permissions{Admin: true, Push: true, Pull: true}
+	case "MAINTAIN", "WRITE":
+		repo.Permissions = Permissions{Push: true, Pull: true}
+	case "TRIAGE", "READ":
+		repo.Permissions = Permissions{Pull: true}
+	}
+
+	return repo
+}
+
+// graphQLReleaseCapabilities is what a GraphQL release node reports: the tagged
+// commit instead of the target branch, and assets without a database ID
+const graphQLReleaseCapabilities = CapDrafts | CapPrereleases | CapReleaseCommitSHA |
+	CapAssetSize | CapAssetDownloadCount | CapAssetCreatedAt | CapAssetContentType
+
+// convertGraphQLRelease converts a GraphQL release node to the standard Release struct.
+// GraphQL exposes neither the API URL nor archive links, so TarballURL and
+// ZipballURL point at the web archive of the tag, and assets have no ID.
+func (p *GitHubProvider) convertGraphQLRelease(node githubGraphQLRelease, repoURL string) Release {
+	tag := url.PathEscape(node.TagName)
+	rel := Release{
+		ID:          node.DatabaseID,
+		TagName:     node.TagName,
+		Name:        node.Name,
+		Body:        node.Description,
+		HTMLUrl:     node.URL,
+		TarballURL:  fmt.Sprintf("%s/archive/refs/tags/%s.tar.gz", repoURL, tag),
+		ZipballURL:  fmt.Sprintf("%s/archive/refs/tags/%s.zip", repoURL, tag),
+		Draft:       node.IsDraft,
+		Prerelease:  node.IsPrerelease,
+		CreatedAt:   node.CreatedAt,
+		PublishedAt: node.PublishedAt,
+		Assets:      make([]Asset, len(node.ReleaseAssets.Nodes)),
+
+		Capabilities: graphQLReleaseCapabilities,
+	}
+	if node.Author != nil {
+		rel.Author = Author{
+			Login:    node.Author.Login,
+			Username: node.Author.Login,
+			FullName: node.Author.Name,
+			Email:    node.Author.Email,
+		}
+	}
+
+	if node.TagCommit != nil {
+		rel.CommitSHA = node.TagCommit.OID
+	}
+
+	for i, a := range node.ReleaseAssets.Nodes {
+		rel.Assets[i] = Asset{
+			Name:               a.Name,
+			Size:               a.Size,
+			DownloadCount:      a.DownloadCount,
+			CreatedAt:          a.CreatedAt,
+			BrowserDownloadURL: a.DownloadURL,
+			ContentType:        a.ContentType,
+			Known:              graphQLReleaseCapabilities & AssetCapabilities,
+		}
+	}
+
+	return rel
+}
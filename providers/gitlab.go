@@ -1,8 +1,14 @@
 package providers
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -17,31 +23,116 @@ func NewGitLabProvider() *GitLabProvider {
 
 // GetReleasesURL constructs the GitLab API URL for fetching releases
 func (p *GitLabProvider) GetReleasesURL(baseURL, user, repo string, latest bool) string {
-	// GitLab uses project path encoding (owner%2Frepo)
-	// Use PathEscape to properly encode the slash
-	projectPath := fmt.Sprintf("%s%%2F%s", user, repo)
+	projectID := p.ProjectID(user, repo)
 	// baseURL already includes /api/v4 from normalizeBaseURL
 	if latest {
-		// GitLab doesn't have a /latest endpoint, we'll fetch all and take first
-		return fmt.Sprintf("%s/projects/%s/releases", baseURL, projectPath)
+		// Available since GitLab 15.4; older instances answer 404 and callers
+		// fall back to the release list (see NormalizeRelease)
+		return fmt.Sprintf("%s/projects/%s/releases/permalink/latest", baseURL, projectID)
 	}
-	return fmt.Sprintf("%s/projects/%s/releases", baseURL, projectPath)
+	return fmt.Sprintf("%s/projects/%s/releases", baseURL, projectID)
+}
+
+// GetReleaseByTagURL constructs the GitLab API URL for the release of a tag
+func (p *GitLabProvider) GetReleaseByTagURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/projects/%s/releases/%s", baseURL, p.ProjectID(user, repo), url.PathEscape(tag))
+}
+
+// GetReleaseByIDURL returns "" because GitLab releases have no ID of their own;
+// the IDs reported by this package are synthetic (see GitLabReleaseID)
+func (p *GitLabProvider) GetReleaseByIDURL(baseURL, user, repo string, id int) string {
+	return ""
 }
 
 // GetRepositoriesURL constructs the GitLab API URL for fetching repositories
 func (p *GitLabProvider) GetRepositoriesURL(baseURL, user string) string {
 	// baseURL already includes /api/v4 from normalizeBaseURL
-	return fmt.Sprintf("%s/users/%s/projects", baseURL, user)
+	return fmt.Sprintf("%s/users/%s/projects", baseURL, url.PathEscape(user))
+}
+
+// GetOrgRepositoriesURL constructs the GitLab API URL for fetching a group's projects
+func (p *GitLabProvider) GetOrgRepositoriesURL(baseURL, org string) string {
+	return p.GetGroupProjectsURL(baseURL, org, false)
+}
+
+// GetAuthenticatedRepositoriesURL constructs the GitLab API URL for fetching the projects
+// the token owner is a member of, including private ones
+func (p *GitLabProvider) GetAuthenticatedRepositoriesURL(baseURL string) string {
+	return fmt.Sprintf("%s/projects?membership=true", baseURL)
+}
+
+// GetGroupProjectsURL constructs the GitLab API URL for fetching the projects of a group.
+// group may be a nested path ("group/subgroup") or a numeric group ID.
+func (p *GitLabProvider) GetGroupProjectsURL(baseURL, group string, includeSubgroups bool) string {
+	apiURL := fmt.Sprintf("%s/groups/%s/projects", baseURL, url.PathEscape(strings.Trim(group, "/")))
+	if includeSubgroups {
+		apiURL += "?include_subgroups=true"
+	}
+	return apiURL
+}
+
+// GetSearchRepositoriesURL constructs the GitLab API URL for searching projects.
+// Owner restricts the search to a user's projects, or to a group's projects including
+// subgroups when OwnerIsGroup is set.
+func (p *GitLabProvider) GetSearchRepositoriesURL(baseURL string, opts SearchOptions) string {
+	q := url.Values{}
+	if opts.Query != "" {
+		q.Set("search", opts.Query)
+	}
+	if opts.Topic != "" {
+		q.Set("topic", opts.Topic)
+	}
+	if opts.Language != "" {
+		q.Set("with_programming_language", opts.Language)
+	}
+	sorts := map[string]string{"name": "name", "stars": "star_count", "created": "created_at", "updated": "last_activity_at"}
+	if sort, ok := sorts[opts.Sort]; ok {
+		q.Set("order_by", sort)
+	}
+	if opts.Order == "asc" || opts.Order == "desc" {
+		q.Set("sort", opts.Order)
+	}
+
+	if opts.Owner != "" && opts.OwnerIsGroup {
+		q.Set("include_subgroups", "true")
+		return fmt.Sprintf("%s/groups/%s/projects?%s", baseURL, url.PathEscape(strings.Trim(opts.Owner, "/")), q.Encode())
+	}
+	if opts.Owner != "" {
+		return fmt.Sprintf("%s/users/%s/projects?%s", baseURL, url.PathEscape(opts.Owner), q.Encode())
+	}
+	return fmt.Sprintf("%s/projects?%s", baseURL, q.Encode())
+}
+
+// NormalizeSearchRepositories converts a GitLab project search response, which is a plain project list
+func (p *GitLabProvider) NormalizeSearchRepositories(data []byte) ([]Repository, error) {
+	return p.NormalizeRepositories(data)
+}
+
+// ProjectID returns the escaped project identifier used in /projects/:id paths.
+// user may be a nested namespace ("group/subgroup"); if repo is empty, user is
+// taken as the full project path or a numeric project ID.
+func (p *GitLabProvider) ProjectID(user, repo string) string {
+	path := strings.Trim(user, "/")
+	repo = strings.Trim(repo, "/")
+	switch {
+	case path == "":
+		path = repo
+	case repo != "":
+		path += "/" + repo
+	}
+	return url.PathEscape(path)
 }
 
 // gitlabRelease represents GitLab's release JSON structure
 type gitlabRelease struct {
-	TagName     string `json:"tag_name"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"created_at"`
-	ReleasedAt  string `json:"released_at"`
-	Author      struct {
+	TagName           string `json:"tag_name"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	CreatedAt         string `json:"created_at"`
+	ReleasedAt        string `json:"released_at"`
+	UpcomingRelease   bool   `json:"upcoming_release"`
+	HistoricalRelease bool   `json:"historical_release"`
+	Author            struct {
 		ID        int    `json:"id"`
 		Username  string `json:"username"`
 		Name      string `json:"name"`
@@ -77,36 +168,35 @@ type gitlabRelease struct {
 	} `json:"_links"`
 }
 
+type gitlabLink struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"`
+}
+
 type gitlabReleaseAssets struct {
-	Count int `json:"count"`
-	Links []struct {
-		ID             int    `json:"id"`
-		Name           string `json:"name"`
-		URL            string `json:"url"`
-		DirectAssetURL string `json:"direct_asset_url"`
-		LinkType       string `json:"link_type"`
-	} `json:"links"`
+	Count   int          `json:"count"`
+	Links   []gitlabLink `json:"links"`
 	Sources []struct {
 		Format string `json:"format"`
 		URL    string `json:"url"`
 	} `json:"sources"`
 }
 
-// NormalizeRelease converts GitLab JSON to the standard Release struct
+// NormalizeRelease converts GitLab JSON to the standard Release struct.
+// With latest set, data may be the single release returned by
+// /releases/permalink/latest or, on older instances, the full release list.
 func (p *GitLabProvider) NormalizeRelease(data []byte, latest bool) ([]Release, error) {
 	var releases []Release
 
-	if latest {
-		var gitlabReleases []gitlabRelease
-		if err := json.Unmarshal(data, &gitlabReleases); err != nil {
+	if latest && !isJSONArray(data) {
+		var glRel gitlabRelease
+		if err := json.Unmarshal(data, &glRel); err != nil {
 			return nil, fmt.Errorf("parse JSON: %w", err)
 		}
-		if len(gitlabReleases) == 0 {
-			return releases, nil
-		}
-		// GitLab doesn't have a /latest endpoint, so we take the first one
-		rel := p.convertGitLabRelease(gitlabReleases[0])
-		releases = append(releases, rel)
+		releases = append(releases, p.convertGitLabRelease(glRel))
 		return releases, nil
 	}
 
@@ -115,6 +205,16 @@ func (p *GitLabProvider) NormalizeRelease(data []byte, latest bool) ([]Release,
 		return nil, fmt.Errorf("parse JSON: %w", err)
 	}
 
+	if latest {
+		if len(gitlabReleases) == 0 {
+			return releases, nil
+		}
+		// The list is sorted by released_at descending, so the first entry is the latest
+		rel := p.convertGitLabRelease(gitlabReleases[0])
+		releases = append(releases, rel)
+		return releases, nil
+	}
+
 	for _, glRel := range gitlabReleases {
 		rel := p.convertGitLabRelease(glRel)
 		releases = append(releases, rel)
@@ -125,26 +225,22 @@ func (p *GitLabProvider) NormalizeRelease(data []byte, latest bool) ([]Release,
 
 // convertGitLabRelease converts a GitLab release to the standard Release struct
 func (p *GitLabProvider) convertGitLabRelease(glRel gitlabRelease) Release {
-	// Generate a simple ID from tag name hash (GitLab doesn't provide a numeric ID)
-	id := 0
-	for _, char := range glRel.TagName {
-		id = id*31 + int(char)
-	}
-	if id < 0 {
-		id = -id
-	}
+	id := GitLabReleaseID(gitlabProjectPath(glRel), glRel.TagName)
 
+	// GitLab has no draft or prerelease flags. An upcoming release (released_at
+	// in the future) is the closest thing to a draft, and prereleases are
+	// inferred from semver prerelease tags such as v1.2.0-rc.1.
 	rel := Release{
 		ID:          id,
 		TagName:     glRel.TagName,
 		Name:        glRel.Name,
-		Body:        strings.ReplaceAll(glRel.Description, "\n", " "),
+		Body:        glRel.Description, // Markdown, kept verbatim
 		URL:         glRel.Links.Self,
 		HTMLUrl:     glRel.TagPath,
-		TarballURL:  "",    // GitLab uses different structure
-		ZipballURL:  "",    // GitLab uses different structure
-		Draft:       false, // GitLab doesn't have draft releases in the same way
-		Prerelease:  false, // GitLab doesn't mark prereleases the same way
+		TarballURL:  "", // GitLab uses different structure
+		ZipballURL:  "", // GitLab uses different structure
+		Draft:       glRel.UpcomingRelease,
+		Prerelease:  isPrereleaseTag(glRel.TagName),
 		CreatedAt:   glRel.CreatedAt,
 		PublishedAt: glRel.ReleasedAt,
 		Author: Author{
@@ -154,22 +250,15 @@ func (p *GitLabProvider) convertGitLabRelease(glRel gitlabRelease) Release {
 			Email:    glRel.Author.Email,
 		},
 		Assets: make([]Asset, len(glRel.Assets.Links)),
+
+		CommitSHA:    glRel.Commit.ID,
+		Capabilities: p.Capabilities(),
 	}
 
 	// Convert GitLab assets
 	// Note: GitLab API doesn't provide Size, DownloadCount, CreatedAt, or UUID for assets
 	for i, glAsset := range glRel.Assets.Links {
-		rel.Assets[i] = Asset{
-			ID:                 glAsset.ID,
-			Name:               glAsset.Name,
-			BrowserDownloadURL: glAsset.DirectAssetURL,
-			Type:               glAsset.LinkType,
-			// Size, DownloadCount, CreatedAt, UUID are not available in GitLab API
-			Size:          0,
-			DownloadCount: 0,
-			CreatedAt:     "",
-			UUID:          "",
-		}
+		rel.Assets[i] = glAsset.toAsset()
 	}
 
 	// Add source links as tarball/zipball if available
@@ -184,27 +273,93 @@ func (p *GitLabProvider) convertGitLabRelease(glRel gitlabRelease) Release {
 	return rel
 }
 
+// GitLabReleaseID returns the synthetic release ID used for GitLab releases,
+// which have no numeric ID of their own. It is the FNV-64a hash of the project
+// path and tag name, truncated to a non-negative int: 63 bits, or 31 bits on
+// 32-bit platforms.
+// The value is stable across calls but is not a GitLab identifier and cannot be
+// used in API requests.
+func GitLabReleaseID(project, tag string) int {
+	h := fnv.New64a()
+	h.Write([]byte(project))
+	h.Write([]byte{0})
+	h.Write([]byte(tag))
+	return int(h.Sum64() & math.MaxInt)
+}
+
+// gitlabProjectPath extracts the project path ("group/project") from a release's
+// tag_path or commit_path, both of which have the form /group/project/-/...
+func gitlabProjectPath(glRel gitlabRelease) string {
+	for _, path := range []string{glRel.TagPath, glRel.CommitPath} {
+		if idx := strings.Index(path, "/-/"); idx >= 0 {
+			return strings.Trim(path[:idx], "/")
+		}
+	}
+	return ""
+}
+
+// isJSONArray reports whether data holds a JSON array.
+func isJSONArray(data []byte) bool {
+	trimmed := strings.TrimLeft(string(data), " \t\r\n")
+	return strings.HasPrefix(trimmed, "[")
+}
+
+// isPrereleaseTag reports whether tag is a semver version with a prerelease part,
+// e.g. "v1.2.0-rc.1" or "2.0.0-beta". A hex commit suffix ("1.2.3-c350f37") is a
+// build identifier, not a prerelease.
+func isPrereleaseTag(tag string) bool {
+	v := strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+	if idx := strings.Index(v, "+"); idx >= 0 {
+		v = v[:idx]
+	}
+	idx := strings.Index(v, "-")
+	if idx <= 0 || idx == len(v)-1 {
+		return false
+	}
+	for _, c := range v[:idx] {
+		if (c < '0' || c > '9') && c != '.' {
+			return false
+		}
+	}
+	return !isHexSuffix(v[idx+1:])
+}
+
+// isHexSuffix reports whether s looks like an abbreviated commit hash.
+func isHexSuffix(s string) bool {
+	if len(s) < 7 {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // gitlabRepository represents GitLab's repository JSON structure
 type gitlabRepository struct {
-	ID                int    `json:"id"`
-	Name              string `json:"name"`
-	Path              string `json:"path"`
-	PathWithNamespace string `json:"path_with_namespace"`
-	Description       string `json:"description"`
-	Visibility        string `json:"visibility"`
-	Fork              bool   `json:"fork"`
-	Size              int64  `json:"size"`
-	Language          string `json:"language"`
-	WebURL            string `json:"web_url"`
-	SSHURL            string `json:"ssh_url_to_repo"`
-	HTTPURL           string `json:"http_url_to_repo"`
-	StarCount         int    `json:"star_count"`
-	ForksCount        int    `json:"forks_count"`
-	OpenIssuesCount   int    `json:"open_issues_count"`
-	DefaultBranch     string `json:"default_branch"`
-	Archived          bool   `json:"archived"`
-	CreatedAt         string `json:"created_at"`
-	LastActivityAt    string `json:"last_activity_at"`
+	ID                int      `json:"id"`
+	Name              string   `json:"name"`
+	Path              string   `json:"path"`
+	PathWithNamespace string   `json:"path_with_namespace"`
+	Description       string   `json:"description"`
+	Visibility        string   `json:"visibility"`
+	Topics            []string `json:"topics"`
+	TagList           []string `json:"tag_list"` // Deprecated name of topics, GitLab < 14.0
+	Fork              bool     `json:"fork"`
+	Size              int64    `json:"size"`
+	Language          string   `json:"language"`
+	WebURL            string   `json:"web_url"`
+	SSHURL            string   `json:"ssh_url_to_repo"`
+	HTTPURL           string   `json:"http_url_to_repo"`
+	StarCount         int      `json:"star_count"`
+	ForksCount        int      `json:"forks_count"`
+	OpenIssuesCount   int      `json:"open_issues_count"`
+	DefaultBranch     string   `json:"default_branch"`
+	Archived          bool     `json:"archived"`
+	CreatedAt         string   `json:"created_at"`
+	LastActivityAt    string   `json:"last_activity_at"`
 	Owner             struct {
 		ID        int    `json:"id"`
 		Username  string `json:"username"`
@@ -238,9 +393,6 @@ func (p *GitLabProvider) NormalizeRepositories(data []byte) ([]Repository, error
 
 // convertGitLabRepository converts a GitLab repository to the standard Repository struct
 func (p *GitLabProvider) convertGitLabRepository(glRepo gitlabRepository) Repository {
-	createdAt, _ := time.Parse(time.RFC3339, glRepo.CreatedAt)
-	updatedAt, _ := time.Parse(time.RFC3339, glRepo.LastActivityAt)
-
 	// Convert size from bytes to KB (approximate)
 	sizeKB := int(glRepo.Size / 1024)
 
@@ -250,6 +402,7 @@ func (p *GitLabProvider) convertGitLabRepository(glRepo gitlabRepository) Reposi
 		FullName:        glRepo.PathWithNamespace,
 		Description:     glRepo.Description,
 		Private:         glRepo.Visibility == "private",
+		Internal:        glRepo.Visibility == "internal",
 		Fork:            glRepo.Fork,
 		Size:            sizeKB,
 		Language:        glRepo.Language,
@@ -261,8 +414,9 @@ func (p *GitLabProvider) convertGitLabRepository(glRepo gitlabRepository) Reposi
 		OpenIssuesCount: glRepo.OpenIssuesCount,
 		DefaultBranch:   glRepo.DefaultBranch,
 		Archived:        glRepo.Archived,
-		CreatedAt:       createdAt.Format(time.RFC3339),
-		UpdatedAt:       updatedAt.Format(time.RFC3339),
+		CreatedAt:       glRepo.CreatedAt,
+		UpdatedAt:       glRepo.LastActivityAt,
+		Capabilities:    p.Capabilities(),
 	}
 
 	repo.Owner = Owner{
@@ -287,6 +441,11 @@ func (p *GitLabProvider) convertGitLabRepository(glRepo gitlabRepository) Reposi
 		Pull:  accessLevel >= 10,
 	}
 
+	repo.Topics = glRepo.Topics
+	if len(repo.Topics) == 0 {
+		repo.Topics = glRepo.TagList
+	}
+
 	// Note: GitLab doesn't provide ReleaseCounter directly in the projects API
 	// To get accurate count, would require fetching /projects/{id}/releases separately
 	// Setting to 0 to indicate unavailability (not a real count)
@@ -308,3 +467,283 @@ func (p *GitLabProvider) DetectProvider(baseURL string) bool {
 	lowerURL := strings.ToLower(baseURL)
 	return strings.Contains(lowerURL, "gitlab.com") || strings.Contains(lowerURL, "gitlab")
 }
+
+// AuthHeader returns the GitLab personal access token header
+func (p *GitLabProvider) AuthHeader(token string) (string, string) {
+	return "PRIVATE-TOKEN", token
+}
+
+// Capabilities returns what GitLab reports. Releases have no draft or
+// prerelease flags and asset links carry no size, date, type or download count
+func (p *GitLabProvider) Capabilities() Capabilities {
+	return CapLatestRelease | CapReleaseCommitSHA | CapAssetID
+}
+
+// gitlabReleasePayload is the release body accepted by the GitLab API
+type gitlabReleasePayload struct {
+	TagName     string `json:"tag_name,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CreateReleaseRequest returns the GitLab API URL and payload that create a release.
+// GitLab has no drafts and derives prereleases from the tag, so specs asking for
+// a draft or for a prerelease state the tag does not imply are rejected.
+func (p *GitLabProvider) CreateReleaseRequest(baseURL, user, repo string, spec ReleaseSpec) (string, interface{}, error) {
+	if err := checkGitLabSpec(spec, false); err != nil {
+		return "", nil, err
+	}
+	payload := gitlabReleasePayload{
+		TagName:     spec.TagName,
+		Ref:         spec.TargetCommitish,
+		Name:        spec.Name,
+		Description: spec.Body,
+	}
+	return p.GetReleasesURL(baseURL, user, repo, false), payload, nil
+}
+
+// UpdateReleaseRequest returns the GitLab API request that edits a release.
+// Only the name and description can change; a release cannot be moved to another tag or commit.
+// An unset Prerelease keeps the state derived from the tag.
+func (p *GitLabProvider) UpdateReleaseRequest(baseURL, user, repo string, current Release, spec ReleaseSpec) (string, string, interface{}, error) {
+	if err := checkGitLabSpec(spec, true); err != nil {
+		return "", "", nil, err
+	}
+	if spec.TagName != current.TagName {
+		return "", "", nil, fmt.Errorf("gitlab: changing the tag of release %q: %w", current.TagName, errors.ErrUnsupported)
+	}
+	if spec.TargetCommitish != "" {
+		return "", "", nil, fmt.Errorf("gitlab: changing the commit of release %q: %w", current.TagName, errors.ErrUnsupported)
+	}
+	payload := gitlabReleasePayload{
+		Name:        spec.Name,
+		Description: spec.Body,
+	}
+	return http.MethodPut, p.GetReleaseByTagURL(baseURL, user, repo, current.TagName), payload, nil
+}
+
+// DeleteReleaseURL returns the GitLab API URL that deletes a release. The tag itself is kept.
+func (p *GitLabProvider) DeleteReleaseURL(baseURL, user, repo string, current Release) string {
+	return p.GetReleaseByTagURL(baseURL, user, repo, current.TagName)
+}
+
+// checkGitLabSpec rejects the parts of spec GitLab cannot represent. An update
+// without Prerelease is not asking to change it, so only a prerelease on a stable
+// tag contradicts the tag there.
+func checkGitLabSpec(spec ReleaseSpec, update bool) error {
+	if spec.Draft {
+		return fmt.Errorf("gitlab: draft releases: %w", errors.ErrUnsupported)
+	}
+	tagPrerelease := isPrereleaseTag(spec.TagName)
+	if spec.Prerelease && !tagPrerelease || !spec.Prerelease && tagPrerelease && !update {
+		return fmt.Errorf("gitlab: prerelease state is derived from the tag and %q cannot be marked prerelease=%t: %w", spec.TagName, spec.Prerelease, errors.ErrUnsupported)
+	}
+	return nil
+}
+
+// toAsset converts a release link.
+// Size, DownloadCount, CreatedAt and UUID are not available in the GitLab API.
+func (l gitlabLink) toAsset() Asset {
+	downloadURL := l.DirectAssetURL
+	if downloadURL == "" {
+		downloadURL = l.URL // direct_asset_url was added in GitLab 13.x
+	}
+	return Asset{
+		ID:                 l.ID,
+		Name:               l.Name,
+		BrowserDownloadURL: downloadURL,
+		Type:               l.LinkType,
+		Known:              CapAssetID,
+	}
+}
+
+// UploadAssetRequest returns the GitLab generic package registry request that stores
+// a file. The package is named after the project and versioned by the release tag.
+func (p *GitLabProvider) UploadAssetRequest(baseURL, user, repo string, release Release, name string) AssetUploadRequest {
+	project := strings.Trim(user+"/"+repo, "/")
+	packageName := project[strings.LastIndex(project, "/")+1:]
+	return AssetUploadRequest{
+		Method: http.MethodPut,
+		URL: fmt.Sprintf("%s/projects/%s/packages/generic/%s/%s/%s", baseURL, p.ProjectID(user, repo),
+			url.PathEscape(packageName), url.PathEscape(release.TagName), url.PathEscape(name)),
+	}
+}
+
+// LinkAssetRequest returns the GitLab API request that links the uploaded package file to release
+func (p *GitLabProvider) LinkAssetRequest(baseURL, user, repo string, release Release, name, uploadURL string) (string, interface{}) {
+	payload := map[string]string{
+		"name":      name,
+		"url":       uploadURL,
+		"link_type": "package",
+	}
+	return p.GetReleaseByTagURL(baseURL, user, repo, release.TagName) + "/assets/links", payload
+}
+
+// NormalizeAsset converts a GitLab release link JSON object to the standard Asset struct
+func (p *GitLabProvider) NormalizeAsset(data []byte) (Asset, error) {
+	var link gitlabLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return Asset{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	return link.toAsset(), nil
+}
+
+// DeleteAssetURL returns the GitLab API URL that deletes the release link of asset.
+// The package file stays in the registry.
+func (p *GitLabProvider) DeleteAssetURL(baseURL, user, repo string, release Release, asset Asset) string {
+	return fmt.Sprintf("%s/assets/links/%d", p.GetReleaseByTagURL(baseURL, user, repo, release.TagName), asset.ID)
+}
+
+// GetTagsURL constructs the GitLab API URL for fetching tags
+func (p *GitLabProvider) GetTagsURL(baseURL, user, repo string) string {
+	return fmt.Sprintf("%s/projects/%s/repository/tags", baseURL, p.ProjectID(user, repo))
+}
+
+// NormalizeTags converts GitLab tag JSON to the standard Tag slice.
+// GitLab tags carry no archive links; build them with ArchiveURL.
+func (p *GitLabProvider) NormalizeTags(data []byte) ([]Tag, error) {
+	var glTags []struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+		Commit  struct {
+			ID            string `json:"id"`
+			CreatedAt     string `json:"created_at"`
+			CommittedDate string `json:"committed_date"`
+		} `json:"commit"`
+	}
+	if err := json.Unmarshal(data, &glTags); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	tags := make([]Tag, len(glTags))
+	for i, gt := range glTags {
+		date := gt.Commit.CommittedDate
+		if date == "" {
+			date = gt.Commit.CreatedAt
+		}
+		tags[i] = Tag{
+			Name:      gt.Name,
+			CommitSHA: gt.Commit.ID,
+			Date:      date,
+			Message:   strings.TrimSpace(gt.Message),
+		}
+	}
+	return tags, nil
+}
+
+// ArchiveURL constructs the GitLab API URL that downloads the repository at ref
+// in format ("tar.gz" or "zip")
+func (p *GitLabProvider) ArchiveURL(baseURL, user, repo, ref, format string) string {
+	return fmt.Sprintf("%s/projects/%s/repository/archive.%s?sha=%s", baseURL, p.ProjectID(user, repo), format, url.QueryEscape(ref))
+}
+
+// GetTagCommitURL constructs the GitLab API URL for a single tag
+func (p *GitLabProvider) GetTagCommitURL(baseURL, user, repo, tag string) string {
+	return fmt.Sprintf("%s/projects/%s/repository/tags/%s", baseURL, p.ProjectID(user, repo), url.PathEscape(tag))
+}
+
+// NormalizeTagCommit extracts the commit SHA from a GitLab tag
+func (p *GitLabProvider) NormalizeTagCommit(data []byte) (string, error) {
+	var tag struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return "", fmt.Errorf("parse JSON: %w", err)
+	}
+	return tag.Commit.ID, nil
+}
+
+// gitlabReleaseHook is the payload of a GitLab "Release Hook" event, which
+// differs from the release API: the tag is "tag" and the project is embedded.
+type gitlabReleaseHook struct {
+	Action      string              `json:"action"`
+	Tag         string              `json:"tag"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	URL         string              `json:"url"`
+	CreatedAt   string              `json:"created_at"`
+	ReleasedAt  string              `json:"released_at"`
+	Assets      gitlabReleaseAssets `json:"assets"`
+	Commit      struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+	Project struct {
+		ID                int    `json:"id"`
+		Name              string `json:"name"`
+		Description       string `json:"description"`
+		WebURL            string `json:"web_url"`
+		GitSSHURL         string `json:"git_ssh_url"`
+		GitHTTPURL        string `json:"git_http_url"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		DefaultBranch     string `json:"default_branch"`
+		VisibilityLevel   int    `json:"visibility_level"`
+	} `json:"project"`
+}
+
+// WebhookEvent returns the X-Gitlab-Event header
+func (p *GitLabProvider) WebhookEvent(h http.Header) string {
+	return h.Get("X-Gitlab-Event")
+}
+
+// VerifyWebhook compares the X-Gitlab-Token header with secret; GitLab does not sign payloads
+func (p *GitLabProvider) VerifyWebhook(h http.Header, body []byte, secret string) bool {
+	token := h.Get("X-Gitlab-Token")
+	return secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+// NormalizeReleaseWebhook decodes a GitLab "Release Hook" event payload
+func (p *GitLabProvider) NormalizeReleaseWebhook(event string, data []byte) (WebhookRelease, bool, error) {
+	if event != "Release Hook" {
+		return WebhookRelease{}, false, nil
+	}
+	var hook gitlabReleaseHook
+	if err := json.Unmarshal(data, &hook); err != nil {
+		return WebhookRelease{}, false, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	glRel := gitlabRelease{
+		TagName:     hook.Tag,
+		Name:        hook.Name,
+		Description: hook.Description,
+		CreatedAt:   hook.CreatedAt,
+		ReleasedAt:  hook.ReleasedAt,
+		Assets:      hook.Assets,
+	}
+	glRel.Commit.ID = hook.Commit.ID
+	// Release hooks use "2006-01-02 15:04:05 UTC", unlike the REST API
+	if released, err := ParseTimestamp(hook.ReleasedAt); err == nil {
+		glRel.UpcomingRelease = released.After(time.Now())
+	}
+	rel := p.convertGitLabRelease(glRel)
+	rel.ID = GitLabReleaseID(hook.Project.PathWithNamespace, hook.Tag)
+	rel.HTMLUrl = hook.URL
+
+	project := hook.Project
+	owner := project.PathWithNamespace
+	if idx := strings.LastIndex(owner, "/"); idx >= 0 {
+		owner = owner[:idx]
+	}
+	repo := Repository{
+		ID:            project.ID,
+		Name:          project.Name,
+		FullName:      project.PathWithNamespace,
+		Description:   project.Description,
+		Private:       project.VisibilityLevel == 0, // GitLab visibility levels: 0=private, 10=internal, 20=public
+		Internal:      project.VisibilityLevel == 10,
+		HTMLURL:       project.WebURL,
+		CloneURL:      project.GitHTTPURL,
+		SSHURL:        project.GitSSHURL,
+		DefaultBranch: project.DefaultBranch,
+		Owner:         Owner{Login: owner, Username: owner},
+	}
+
+	actions := map[string]WebhookAction{
+		"create": WebhookPublished,
+		"update": WebhookUpdated,
+		"delete": WebhookDeleted,
+	}
+	return WebhookRelease{Action: actions[hook.Action], RawAction: hook.Action, Release: rel, Repository: repo}, true, nil
+}
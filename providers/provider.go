@@ -1,7 +1,11 @@
 // Package providers defines interfaces and implementations for different Git hosting platforms.
 package providers
 
+import "net/http"
+
 // Provider defines the interface that all Git hosting providers must implement.
+// Optional features are separate interfaces that embed Provider, such as
+// Authenticator; callers check for them with a type assertion.
 type Provider interface {
 	// GetReleasesURL constructs the API URL for fetching releases
 	GetReleasesURL(baseURL, user, repo string, latest bool) string
@@ -19,6 +23,125 @@ type Provider interface {
 	DetectProvider(baseURL string) bool
 }
 
+// ReleaseFinder extends Provider with the URLs of single releases.
+type ReleaseFinder interface {
+	Provider
+
+	// GetReleaseByTagURL constructs the API URL for the release of a single tag
+	GetReleaseByTagURL(baseURL, user, repo, tag string) string
+
+	// GetReleaseByIDURL constructs the API URL for a release by ID, or returns ""
+	// when the provider cannot address releases by ID
+	GetReleaseByIDURL(baseURL, user, repo string, id int) string
+}
+
+// TagLister extends Provider with listing tags and resolving them to commits.
+type TagLister interface {
+	Provider
+
+	// GetTagsURL constructs the API URL for fetching a repository's tags
+	GetTagsURL(baseURL, user, repo string) string
+
+	// NormalizeTags converts provider-specific tag JSON to the standard Tag slice
+	NormalizeTags(data []byte) ([]Tag, error)
+
+	// GetTagCommitURL constructs the API URL that resolves a tag to its commit
+	GetTagCommitURL(baseURL, user, repo, tag string) string
+
+	// NormalizeTagCommit extracts the full commit SHA from the GetTagCommitURL response
+	NormalizeTagCommit(data []byte) (string, error)
+}
+
+// OwnerLister extends Provider with the repository listings of organizations
+// and of the token owner.
+type OwnerLister interface {
+	Provider
+
+	// GetOrgRepositoriesURL constructs the API URL for fetching an organization's (or GitLab group's) repositories
+	GetOrgRepositoriesURL(baseURL, org string) string
+
+	// GetAuthenticatedRepositoriesURL constructs the API URL for fetching the token owner's repositories
+	GetAuthenticatedRepositoriesURL(baseURL string) string
+}
+
+// RepositorySearcher extends Provider with repository search.
+type RepositorySearcher interface {
+	Provider
+
+	// GetSearchRepositoriesURL constructs the API URL for searching repositories
+	GetSearchRepositoriesURL(baseURL string, opts SearchOptions) string
+
+	// NormalizeSearchRepositories converts a provider-specific search response to the standard Repository slice
+	NormalizeSearchRepositories(data []byte) ([]Repository, error)
+}
+
+// Authenticator extends Provider with the header used to send API tokens.
+// Providers without it get "Authorization: token <token>".
+type Authenticator interface {
+	Provider
+
+	// AuthHeader returns the header name and value used to authenticate with token
+	AuthHeader(token string) (string, string)
+}
+
+// CapabilityReporter extends Provider with the fields its REST API reports.
+// Providers without it report no capabilities.
+type CapabilityReporter interface {
+	Provider
+
+	// Capabilities returns what the provider's REST API reports; fields outside
+	// it are zero because they are unknown
+	Capabilities() Capabilities
+}
+
+// ReleaseWriter extends Provider with the requests that create, edit and delete releases.
+// Specs a provider cannot honour are rejected with an error wrapping errors.ErrUnsupported.
+type ReleaseWriter interface {
+	Provider
+
+	// CreateReleaseRequest returns the URL and JSON payload to POST to create a release
+	CreateReleaseRequest(baseURL, user, repo string, spec ReleaseSpec) (string, interface{}, error)
+
+	// UpdateReleaseRequest returns the method, URL and JSON payload that replace current's metadata with spec
+	UpdateReleaseRequest(baseURL, user, repo string, current Release, spec ReleaseSpec) (string, string, interface{}, error)
+
+	// DeleteReleaseURL returns the URL to DELETE to remove current
+	DeleteReleaseURL(baseURL, user, repo string, current Release) string
+}
+
+// AssetWriter extends ReleaseWriter with the requests that upload and delete release assets.
+type AssetWriter interface {
+	ReleaseWriter
+
+	// UploadAssetRequest describes the request that uploads the file called name for release
+	UploadAssetRequest(baseURL, user, repo string, release Release, name string) AssetUploadRequest
+
+	// LinkAssetRequest returns the URL and JSON payload to POST to attach an uploaded file
+	// to release, or "" when the upload already attached it
+	LinkAssetRequest(baseURL, user, repo string, release Release, name, uploadURL string) (string, interface{})
+
+	// NormalizeAsset converts the provider-specific upload or link response to the standard Asset struct
+	NormalizeAsset(data []byte) (Asset, error)
+
+	// DeleteAssetURL returns the URL to DELETE to remove asset from release
+	DeleteAssetURL(baseURL, user, repo string, release Release, asset Asset) string
+}
+
+// WebhookParser extends Provider with the validation and decoding of release webhooks.
+type WebhookParser interface {
+	Provider
+
+	// WebhookEvent returns the event name from the provider's webhook headers, or "" if
+	// the request was not sent by this provider
+	WebhookEvent(h http.Header) string
+
+	// VerifyWebhook reports whether body carries a valid signature or token for secret
+	VerifyWebhook(h http.Header, body []byte, secret string) bool
+
+	// NormalizeReleaseWebhook decodes a release event payload; ok is false for other events
+	NormalizeReleaseWebhook(event string, data []byte) (hook WebhookRelease, ok bool, err error)
+}
+
 // ProviderType represents the type of Git hosting provider
 type ProviderType string
 
@@ -26,6 +149,9 @@ const (
 	ProviderGitea  ProviderType = "gitea"
 	ProviderGitHub ProviderType = "github"
 	ProviderGitLab ProviderType = "gitlab"
+
+	// ProviderGitHubEnterprise is a GitHub Enterprise Server instance, served by GitHubProvider
+	ProviderGitHubEnterprise ProviderType = "ghes"
 )
 
 // GetProvider returns the appropriate provider implementation based on the type or auto-detection
@@ -33,7 +159,7 @@ func GetProvider(providerType ProviderType, baseURL string) Provider {
 	// If provider type is explicitly set, use it
 	if providerType != "" {
 		switch providerType {
-		case ProviderGitHub:
+		case ProviderGitHub, ProviderGitHubEnterprise:
 			return &GitHubProvider{}
 		case ProviderGitLab:
 			return &GitLabProvider{}
@@ -1,6 +1,12 @@
 // Package providers defines interfaces and implementations for different Git hosting platforms.
 package providers
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Release represents a normalized release structure used by providers
 type Release struct {
 	ID          int
@@ -17,6 +23,28 @@ type Release struct {
 	PublishedAt string
 	Author      Author
 	Assets      []Asset
+
+	// TargetCommitish is the branch or SHA the release was created from, as reported
+	// by Gitea and GitHub. CommitSHA is the full SHA of the tagged commit when the
+	// response carries it; ResolveReleaseCommit fills it in otherwise.
+	TargetCommitish string
+	CommitSHA       string
+
+	// UploadURL is GitHub's upload_url template for the release's assets
+	UploadURL string
+
+	// Capabilities tells which fields the provider reported; see Asset.Known for assets
+	Capabilities Capabilities
+}
+
+// Tag represents a normalized git tag
+type Tag struct {
+	Name       string
+	CommitSHA  string
+	Date       string // Commit date, RFC3339; empty when the provider does not report it
+	Message    string
+	TarballURL string
+	ZipballURL string
 }
 
 // Author represents the author of a release
@@ -32,12 +60,17 @@ type Author struct {
 type Asset struct {
 	ID                 int
 	Name               string
+	URL                string // API URL of the asset, if the provider exposes one
 	Size               int64
 	DownloadCount      int
 	CreatedAt          string
 	UUID               string
 	BrowserDownloadURL string
-	Type               string
+	Type               string // GitLab link type ("package", "image", ...)
+	ContentType        string // MIME type
+
+	// Known holds the AssetCapabilities of the fields the provider reported for this asset
+	Known Capabilities
 }
 
 // Repository represents a normalized repository structure used by providers
@@ -47,6 +80,7 @@ type Repository struct {
 	FullName        string
 	Description     string
 	Private         bool
+	Internal        bool
 	Fork            bool
 	Size            int
 	Language        string
@@ -69,6 +103,10 @@ type Repository struct {
 	HasProjects     bool
 	HasReleases     bool
 	HasPackages     bool
+	Topics          []string
+
+	// Capabilities tells which fields the provider reported, such as CapReleaseCount
+	Capabilities Capabilities
 }
 
 // Owner represents repository owner information
@@ -87,3 +125,98 @@ type Permissions struct {
 	Push  bool
 	Pull  bool
 }
+
+// SearchOptions describes a repository search. Sort is one of "name", "stars",
+// "forks", "created" or "updated"; values a provider cannot sort by are ignored.
+// Order is "asc" or "desc". Owner narrows the search to one user or organization
+// where the provider supports it; OwnerIsGroup tells an organization or GitLab
+// group from a user.
+type SearchOptions struct {
+	Query        string
+	Topic        string
+	Language     string
+	Sort         string
+	Order        string
+	Owner        string
+	OwnerIsGroup bool
+}
+
+// ReleaseSpec describes a release to create or the new state of an existing one.
+// TargetCommitish is only used when the tag does not exist yet.
+type ReleaseSpec struct {
+	TagName         string
+	TargetCommitish string
+	Name            string
+	Body            string
+	Draft           bool
+	Prerelease      bool
+}
+
+// IsCommitSHA reports whether s is a full SHA-1 or SHA-256 commit ID
+func IsCommitSHA(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// timestampLayouts are the timestamp formats used by the supported APIs, webhooks and git
+var timestampLayouts = []string{
+	time.RFC3339Nano,            // 2024-05-01T10:00:00Z, 2024-05-01T10:00:00.539+02:00
+	"2006-01-02T15:04:05Z0700",  // Offset without a colon
+	"2006-01-02T15:04:05",       // No zone, taken as UTC
+	"2006-01-02 15:04:05 MST",   // GitLab webhooks: 2024-05-01 10:00:00 UTC
+	"2006-01-02 15:04:05 -0700", // git
+	time.RFC1123,                // HTTP Last-Modified
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a timestamp in any format the providers use. An empty
+// string is the zero time, meaning unknown, and not an error.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse timestamp %q: unknown format", s)
+}
+
+// releasePayload is the release body accepted by the Gitea and GitHub APIs
+type releasePayload struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name"`
+	Body            string `json:"body"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+}
+
+func newReleasePayload(spec ReleaseSpec) releasePayload {
+	return releasePayload{
+		TagName:         spec.TagName,
+		TargetCommitish: spec.TargetCommitish,
+		Name:            spec.Name,
+		Body:            spec.Body,
+		Draft:           spec.Draft,
+		Prerelease:      spec.Prerelease,
+	}
+}
+
+// AssetUploadRequest describes how a provider expects a release asset to be uploaded.
+// With FormField set the file is sent as that multipart/form-data field; otherwise
+// it is the raw request body.
+type AssetUploadRequest struct {
+	Method    string
+	URL       string
+	FormField string
+}
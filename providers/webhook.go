@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// WebhookAction is the provider-neutral action of a release webhook.
+type WebhookAction string
+
+const (
+	WebhookCreated     WebhookAction = "created"     // A draft release was created (GitHub)
+	WebhookPublished   WebhookAction = "published"   // A release was published
+	WebhookUpdated     WebhookAction = "updated"     // A release was edited
+	WebhookDeleted     WebhookAction = "deleted"     // A release was deleted
+	WebhookUnpublished WebhookAction = "unpublished" // A release was turned back into a draft (GitHub)
+)
+
+// WebhookRelease is a decoded release webhook. Action is empty for actions that
+// repeat another event, such as GitHub's "released" after "published".
+type WebhookRelease struct {
+	Action     WebhookAction
+	RawAction  string
+	Release    Release
+	Repository Repository
+}
+
+// releaseWebhook is the envelope of Gitea and GitHub release webhooks.
+type releaseWebhook struct {
+	Action     string          `json:"action"`
+	Release    json.RawMessage `json:"release"`
+	Repository json.RawMessage `json:"repository"`
+}
+
+// normalizeReleaseWebhook decodes a Gitea or GitHub release payload with p's
+// release and repository parsers.
+func normalizeReleaseWebhook(p Provider, data []byte, actions map[string]WebhookAction) (WebhookRelease, error) {
+	var hook releaseWebhook
+	if err := json.Unmarshal(data, &hook); err != nil {
+		return WebhookRelease{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	if len(hook.Release) == 0 || len(hook.Repository) == 0 {
+		return WebhookRelease{}, fmt.Errorf("parse JSON: release webhook without release or repository")
+	}
+
+	releases, err := p.NormalizeRelease(hook.Release, true)
+	if err != nil {
+		return WebhookRelease{}, err
+	}
+	repos, err := p.NormalizeRepositories([]byte("[" + string(hook.Repository) + "]"))
+	if err != nil {
+		return WebhookRelease{}, err
+	}
+	return WebhookRelease{
+		Action:     actions[hook.Action],
+		RawAction:  hook.Action,
+		Release:    releases[0],
+		Repository: repos[0],
+	}, nil
+}
+
+// validHMAC reports whether signature is the hex HMAC-SHA256 of body keyed with secret.
+func validHMAC(signature string, body []byte, secret string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,185 @@
+package gitearelease
+
+import (
+	"cmp"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReleaseFilter reports whether a release should be kept. Filters combine
+// with AllOf, AnyOf and Not, and can be passed to GetReleases in
+// ReleaseToFetch.Filter so pagination stops once MaxResults releases match.
+type ReleaseFilter func(Release) bool
+
+// FilterReleases returns the releases matching every filter, in their original order.
+func FilterReleases(releases []Release, filters ...ReleaseFilter) []Release {
+	keep := AllOf(filters...)
+	matched := []Release{}
+	for _, rel := range releases {
+		if keep(rel) {
+			matched = append(matched, rel)
+		}
+	}
+	return matched
+}
+
+// AllOf matches releases that match every filter; with no filters it matches all.
+func AllOf(filters ...ReleaseFilter) ReleaseFilter {
+	return func(rel Release) bool {
+		for _, f := range filters {
+			if !f(rel) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf matches releases that match at least one filter.
+func AnyOf(filters ...ReleaseFilter) ReleaseFilter {
+	return func(rel Release) bool {
+		for _, f := range filters {
+			if f(rel) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches the releases f does not.
+func Not(f ReleaseFilter) ReleaseFilter {
+	return func(rel Release) bool {
+		return !f(rel)
+	}
+}
+
+// ExcludeDrafts matches published releases.
+func ExcludeDrafts() ReleaseFilter {
+	return func(rel Release) bool {
+		return !rel.Draft
+	}
+}
+
+// ExcludePrereleases matches stable releases.
+func ExcludePrereleases() ReleaseFilter {
+	return func(rel Release) bool {
+		return !rel.Prerelease
+	}
+}
+
+// OnlyPrereleases matches prereleases.
+func OnlyPrereleases() ReleaseFilter {
+	return func(rel Release) bool {
+		return rel.Prerelease
+	}
+}
+
+// PublishedAfter matches releases whose ReleaseTime is after t; releases
+// without a known time never match.
+func PublishedAfter(t time.Time) ReleaseFilter {
+	return func(rel Release) bool {
+		rt := rel.ReleaseTime()
+		return !rt.IsZero() && rt.After(t)
+	}
+}
+
+// PublishedWithin matches releases published during the last d, counted from
+// when the filter is created.
+func PublishedWithin(d time.Duration) ReleaseFilter {
+	return PublishedAfter(time.Now().Add(-d))
+}
+
+// TagMatches matches releases whose tag matches re.
+func TagMatches(re *regexp.Regexp) ReleaseFilter {
+	return func(rel Release) bool {
+		return re.MatchString(rel.TagName)
+	}
+}
+
+// HasAsset matches releases with an asset whose name matches the path.Match
+// pattern, such as "*.deb".
+func HasAsset(pattern string) ReleaseFilter {
+	return func(rel Release) bool {
+		for _, a := range rel.Assets {
+			if ok, _ := path.Match(pattern, a.Name); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasPlatformAsset matches releases with an asset built for goos and goarch,
+// as classified by AssetPlatform.
+func HasPlatformAsset(goos, goarch string) ReleaseFilter {
+	return func(rel Release) bool {
+		for _, a := range rel.Assets {
+			if assetOS, assetArch := AssetPlatform(a.Name); assetOS == goos && assetArch == goarch {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ReleaseOrder compares two releases for SortReleases. It returns a negative
+// number when a sorts before b, a positive one when after and 0 when tied.
+type ReleaseOrder func(a, b Release) int
+
+// SortReleases sorts releases by the first order, breaking ties with the
+// following ones. The sort is stable.
+func SortReleases(releases []Release, orders ...ReleaseOrder) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		for _, order := range orders {
+			if c := order(releases[i], releases[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// Descending reverses order.
+func Descending(order ReleaseOrder) ReleaseOrder {
+	return func(a, b Release) int {
+		return order(b, a)
+	}
+}
+
+// BySemver orders releases by the version in their tag, oldest first, as CompareVersions does.
+// Tags CompareVersions cannot order either way round, such as versions that differ
+// only in build metadata, are ordered by tag name and then ID.
+func BySemver(a, b Release) int {
+	c := CompareVersions(VersionStrings{Own: a.TagName, Latest: b.TagName})
+	if c != 0 && c == -CompareVersions(VersionStrings{Own: b.TagName, Latest: a.TagName}) {
+		return c
+	}
+	if c := strings.Compare(a.TagName, b.TagName); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// ByPublished orders releases by ReleaseTime, oldest first. Releases without a
+// known time sort before all others.
+func ByPublished(a, b Release) int {
+	return a.ReleaseTime().Compare(b.ReleaseTime())
+}
+
+// ByDownloads orders releases by the total download count of their assets, lowest first.
+func ByDownloads(a, b Release) int {
+	return totalDownloads(a) - totalDownloads(b)
+}
+
+// totalDownloads sums the download counts of the assets of rel.
+func totalDownloads(rel Release) int {
+	total := 0
+	for _, a := range rel.Assets {
+		total += a.DownloadCount
+	}
+	return total
+}
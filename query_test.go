@@ -0,0 +1,174 @@
+package gitearelease
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func testQueryReleases() []Release {
+	now := time.Now().UTC()
+	return []Release{
+		{TagName: "v2.0.0-rc.1", Prerelease: true, PublishedAt: now.AddDate(0, 0, -2).Format(time.RFC3339),
+			Assets: []Asset{{Name: "tool_linux_amd64.tar.gz", DownloadCount: 5}}},
+		{TagName: "v1.10.0", PublishedAt: now.AddDate(0, 0, -30).Format(time.RFC3339),
+			Assets: []Asset{{Name: "tool_Linux_x86_64.tar.gz", DownloadCount: 40}, {Name: "checksums.txt", DownloadCount: 3}}},
+		{TagName: "v1.9.0", Draft: true,
+			Assets: []Asset{{Name: "tool_darwin_arm64.zip"}}},
+		{TagName: "v1.2.0", PublishedAt: now.AddDate(-1, 0, 0).Format(time.RFC3339),
+			Assets: []Asset{{Name: "tool_windows_amd64.exe", DownloadCount: 100}}},
+	}
+}
+
+func releaseTags(releases []Release) string {
+	var names []string
+	for _, rel := range releases {
+		names = append(names, rel.TagName)
+	}
+	return fmt.Sprint(names)
+}
+
+func TestFilterReleases(t *testing.T) {
+	releases := testQueryReleases()
+	tests := []struct {
+		name    string
+		filters []ReleaseFilter
+		want    string
+	}{
+		{"no filters", nil, "[v2.0.0-rc.1 v1.10.0 v1.9.0 v1.2.0]"},
+		{"exclude drafts", []ReleaseFilter{ExcludeDrafts()}, "[v2.0.0-rc.1 v1.10.0 v1.2.0]"},
+		{"only prereleases", []ReleaseFilter{OnlyPrereleases()}, "[v2.0.0-rc.1]"},
+		{"within 90 days", []ReleaseFilter{PublishedWithin(90 * 24 * time.Hour)}, "[v2.0.0-rc.1 v1.10.0]"},
+		{"tag regex", []ReleaseFilter{TagMatches(regexp.MustCompile(`^v1\.\d+\.0$`))}, "[v1.10.0 v1.9.0 v1.2.0]"},
+		{"linux/amd64 asset", []ReleaseFilter{HasPlatformAsset("linux", "amd64")}, "[v2.0.0-rc.1 v1.10.0]"},
+		{"asset glob", []ReleaseFilter{HasAsset("*.zip")}, "[v1.9.0]"},
+		{"stable linux", []ReleaseFilter{ExcludeDrafts(), Not(OnlyPrereleases()), HasPlatformAsset("linux", "amd64")}, "[v1.10.0]"},
+		{"any of", []ReleaseFilter{AnyOf(HasAsset("*.exe"), OnlyPrereleases())}, "[v2.0.0-rc.1 v1.2.0]"},
+	}
+	for _, tt := range tests {
+		if got := releaseTags(FilterReleases(releases, tt.filters...)); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSortReleases(t *testing.T) {
+	releases := testQueryReleases()
+
+	SortReleases(releases, BySemver)
+	if got := releaseTags(releases); got != "[v1.2.0 v1.9.0 v1.10.0 v2.0.0-rc.1]" {
+		t.Errorf("Expected semver order, got %s", got)
+	}
+
+	SortReleases(releases, Descending(ByDownloads))
+	if got := releaseTags(releases); got != "[v1.2.0 v1.10.0 v2.0.0-rc.1 v1.9.0]" {
+		t.Errorf("Expected most downloaded first, got %s", got)
+	}
+
+	SortReleases(releases, Descending(ByPublished))
+	if got := releaseTags(releases); got != "[v2.0.0-rc.1 v1.10.0 v1.2.0 v1.9.0]" {
+		t.Errorf("Expected newest first with the undated draft last, got %s", got)
+	}
+}
+
+func TestBySemver_BuildMetadataTieBreak(t *testing.T) {
+	a := Release{ID: 2, TagName: "v1.0.0+abc1234"}
+	b := Release{ID: 1, TagName: "v1.0.0+def5678"}
+	if BySemver(a, b) != -BySemver(b, a) || BySemver(a, b) == 0 {
+		t.Errorf("Expected an antisymmetric order, got %d and %d", BySemver(a, b), BySemver(b, a))
+	}
+	if BySemver(a, b) >= 0 {
+		t.Errorf("Expected ties to be broken by tag name, got %d", BySemver(a, b))
+	}
+
+	c := Release{ID: 3, TagName: "v1.0.0+abc1234"}
+	if BySemver(a, c) >= 0 || BySemver(c, a) <= 0 {
+		t.Errorf("Expected equal tags to be ordered by ID, got %d and %d", BySemver(a, c), BySemver(c, a))
+	}
+
+	for _, releases := range [][]Release{{a, b, c}, {c, b, a}, {b, c, a}} {
+		SortReleases(releases, BySemver)
+		if releases[0].ID != 2 || releases[1].ID != 3 || releases[2].ID != 1 {
+			t.Errorf("Expected the same order whatever the input order, got %+v", releases)
+		}
+	}
+}
+
+func TestGetReleases_FilterStopsPaginating(t *testing.T) {
+	var requested []string
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		requested = append(requested, page)
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page=100, got %s", r.URL.RawQuery)
+		}
+		switch page {
+		case "1":
+			w.Header().Set("Link", `<`+mockServer.URL+`/repos/o/app/releases?page=2&per_page=100>; rel="next"`)
+			w.Write([]byte(`[{"id": 4, "tag_name": "v2.0.0-rc.1", "prerelease": true}, {"id": 3, "tag_name": "v1.2.0", "draft": true}]`))
+		case "2":
+			w.Header().Set("Link", `<`+mockServer.URL+`/repos/o/app/releases?page=3&per_page=100>; rel="next"`)
+			w.Write([]byte(`[{"id": 2, "tag_name": "v1.1.0"}, {"id": 1, "tag_name": "v1.0.0"}]`))
+		default:
+			t.Errorf("Expected pagination to stop after page 2, got page %s", page)
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	releases, err := GetReleases(ReleaseToFetch{
+		BaseURL:    mockServer.URL,
+		User:       "o",
+		Repo:       "app",
+		Provider:   "github",
+		Filter:     AllOf(ExcludeDrafts(), ExcludePrereleases()),
+		MaxResults: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := releaseTags(releases); got != "[v1.1.0]" {
+		t.Errorf("Expected [v1.1.0], got %s", got)
+	}
+	if len(requested) != 2 {
+		t.Errorf("Expected 2 page requests, got %v", requested)
+	}
+}
+
+func TestAssetPlatform(t *testing.T) {
+	tests := []struct {
+		name, goos, goarch string
+	}{
+		{"tool_1.2.0_Linux_x86_64.tar.gz", "linux", "amd64"},
+		{"tool-linux-aarch64", "linux", "arm64"},
+		{"tool_linux_armv7.tar.gz", "linux", "arm"},
+		{"tool_darwin_arm64.zip", "darwin", "arm64"},
+		{"tool-macos-universal.zip", "darwin", "all"},
+		{"tool_windows_386.zip", "windows", "386"},
+		{"tool-x64.exe", "windows", "amd64"},
+		{"tool_freebsd_i686.tar.gz", "freebsd", "386"},
+		{"alarm_windows_386.exe", "windows", "386"},
+		{"swarm_linux_386.tar.gz", "linux", "386"},
+		{"pineapple_linux_arm64.tar.gz", "linux", "arm64"},
+		{"tool-x86_64-apple-darwin.tar.gz", "darwin", "amd64"},
+		{"tool_linux_x86-64.tar.gz", "linux", "amd64"},
+		{"xx64tool_linux_armv6.tar.gz", "linux", "arm"},
+		{"tool.exe", "windows", ""},
+		{"tool_linux_amd64.tar.gz.sha256", "", ""},
+		{"checksums.txt", "", ""},
+		{"source.tar.gz", "", ""},
+	}
+	for _, tt := range tests {
+		goos, goarch := AssetPlatform(tt.name)
+		if goos != tt.goos || goarch != tt.goarch {
+			t.Errorf("%s: expected %s/%s, got %s/%s", tt.name, tt.goos, tt.goarch, goos, goarch)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package gitearelease
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by the terminal renderer.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiRed       = "\x1b[31m"
+	ansiCyan      = "\x1b[36m"
+	ansiDim       = "\x1b[2m"
+)
+
+// defaultRenderWidth is used when RenderOptions.Width is not set.
+const defaultRenderWidth = 80
+
+// RenderOptions controls RenderReleaseNotes and RenderMarkdown.
+type RenderOptions struct {
+	Width         int  // Wrap width in columns; 0 means 80
+	NoColor       bool // Plain text without ANSI escape sequences
+	BreakingFirst bool // Repeat the breaking changes in a highlighted block at the top
+}
+
+// RenderReleaseNotes renders rel's title and Markdown body for a terminal:
+// styled headings, bulleted lists, highlighted code spans, links numbered as
+// footnotes, and paragraphs wrapped to opts.Width.
+func RenderReleaseNotes(rel Release, opts RenderOptions) string {
+	r := newNotesRenderer(opts)
+	r.heading(1, entryTitle(rel))
+	if opts.BreakingFirst {
+		if breaking := BreakingChanges(rel); len(breaking) > 0 {
+			r.block()
+			r.line(r.style(ansiBold+ansiRed, "⚠ Breaking changes"))
+			for _, item := range breaking {
+				r.wrap(r.inline(item), "  • ", "    ")
+			}
+		}
+	}
+	r.render(rel.Body)
+	return r.finish()
+}
+
+// RenderMarkdown renders a Markdown document for a terminal; see RenderReleaseNotes.
+func RenderMarkdown(markdown string, opts RenderOptions) string {
+	r := newNotesRenderer(opts)
+	r.render(markdown)
+	return r.finish()
+}
+
+// BreakingChanges returns the items of rel's breaking-change sections: those
+// under a heading such as "BREAKING CHANGES" and "BREAKING CHANGE:" lines.
+func BreakingChanges(rel Release) []string {
+	var items []string
+	for _, s := range parseSections(rel.Body) {
+		if s.Kind == SectionBreaking {
+			items = append(items, s.Items...)
+		}
+	}
+	return items
+}
+
+// notesRenderer accumulates rendered output and link footnotes.
+type notesRenderer struct {
+	opts  RenderOptions
+	width int
+	b     strings.Builder
+	links []string
+	// blank is true when the output ends with an empty line (or nothing at all)
+	blank bool
+}
+
+func newNotesRenderer(opts RenderOptions) *notesRenderer {
+	width := opts.Width
+	if width <= 0 {
+		width = defaultRenderWidth
+	}
+	if width < 20 {
+		width = 20
+	}
+	return &notesRenderer{opts: opts, width: width, blank: true}
+}
+
+var (
+	fencePattern = regexp.MustCompile("^\\s*(```|~~~)")
+	rulePattern  = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	quotePattern = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	itemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+)
+
+// render converts the block structure of markdown.
+func (r *notesRenderer) render(markdown string) {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var para []string
+	first, rest := "", ""
+	flush := func() {
+		if len(para) > 0 {
+			r.wrap(r.inline(strings.Join(para, " ")), first, rest)
+			para = nil
+		}
+	}
+	inList := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fencePattern.MatchString(line):
+			flush()
+			r.block()
+			fence := fencePattern.FindStringSubmatch(line)[1]
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				r.line("    " + r.style(ansiDim, lines[i]))
+			}
+			r.blank = false
+			inList = false
+		case trimmed == "":
+			flush()
+			if !r.blank && !inList {
+				r.line("")
+			}
+		case headingPattern.MatchString(line):
+			flush()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			r.heading(level, headingPattern.FindStringSubmatch(line)[1])
+			inList = false
+		case rulePattern.MatchString(line):
+			flush()
+			r.block()
+			r.line(r.style(ansiDim, strings.Repeat("─", r.width)))
+			inList = false
+		case quotePattern.MatchString(line):
+			flush()
+			if !inList {
+				r.block()
+			}
+			r.wrap(r.inline(quotePattern.FindStringSubmatch(line)[1]), "│ ", "│ ")
+		case itemPattern.MatchString(line):
+			flush()
+			if !inList {
+				r.block()
+			}
+			m := itemPattern.FindStringSubmatch(line)
+			indent := strings.Repeat("  ", len(strings.ReplaceAll(m[1], "\t", "  "))/2)
+			marker := "•"
+			if m[2][0] >= '0' && m[2][0] <= '9' {
+				marker = m[2]
+			}
+			first = "  " + indent + marker + " "
+			rest = strings.Repeat(" ", utf8.RuneCountInString(first))
+			para = append(para, m[3])
+			inList = true
+		default:
+			if len(para) == 0 {
+				if inList && line != trimmed {
+					// Indented text continues the previous list item on a new line
+					para = append(para, trimmed)
+					first = rest
+					continue
+				}
+				if !inList {
+					r.block()
+				}
+				first, rest = "", ""
+				inList = false
+			}
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+}
+
+// heading writes a heading, underlined for the top two levels.
+func (r *notesRenderer) heading(level int, text string) {
+	r.block()
+	style := ansiBold
+	if level <= 2 {
+		style += ansiUnderline
+	}
+	text = stripMarkdown(text)
+	r.line(r.style(style, text))
+	if r.opts.NoColor && level <= 2 {
+		underline := "-"
+		if level == 1 {
+			underline = "="
+		}
+		r.line(strings.Repeat(underline, utf8.RuneCountInString(text)))
+	}
+}
+
+// block separates the next block from the previous one with an empty line.
+func (r *notesRenderer) block() {
+	if !r.blank {
+		r.line("")
+	}
+}
+
+func (r *notesRenderer) line(s string) {
+	r.b.WriteString(strings.TrimRight(s, " ") + "\n")
+	r.blank = s == ""
+}
+
+// wrap writes text word-wrapped to the width, starting with first and indenting
+// continuation lines with rest. ANSI sequences do not count towards the width.
+func (r *notesRenderer) wrap(text, first, rest string) {
+	prefix := first
+	current := prefix
+	width := visibleWidth(prefix)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		w := visibleWidth(word)
+		if !empty && width+1+w > r.width {
+			r.line(current)
+			prefix = rest
+			current, width, empty = prefix, visibleWidth(prefix), true
+		}
+		if !empty {
+			current += " "
+			width++
+		}
+		current += word
+		width += w
+		empty = false
+	}
+	r.line(current)
+}
+
+var (
+	ansiPattern    = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	inlineLink     = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	autoLink       = regexp.MustCompile(`<(https?://[^>]+)>`)
+	boldPattern    = regexp.MustCompile(`(\*\*|__)([^*_]+?)(\*\*|__)`)
+	italicPattern  = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\s][^*_]*?)[*_]([^\w*]|$)`)
+	strikePattern  = regexp.MustCompile(`~~([^~]+)~~`)
+	breakingMarker = regexp.MustCompile(`^BREAKING[ -]CHANGES?:`)
+)
+
+// inline applies inline Markdown: code spans, links (as footnotes), bold, italics.
+func (r *notesRenderer) inline(s string) string {
+	parts := strings.Split(s, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			if r.opts.NoColor {
+				parts[i] = "`" + part + "`"
+			} else {
+				parts[i] = r.style(ansiCyan, part)
+			}
+			continue
+		}
+		if i%2 == 1 {
+			part = "`" + part // unbalanced backtick
+		}
+		part = autoLink.ReplaceAllString(part, "[$1]($1)")
+		part = inlineLink.ReplaceAllStringFunc(part, func(m string) string {
+			sub := inlineLink.FindStringSubmatch(m)
+			text, target := sub[1], sub[2]
+			if text == "" || text == target {
+				return r.style(ansiUnderline, target)
+			}
+			r.links = append(r.links, target)
+			return fmt.Sprintf("%s[%d]", r.style(ansiUnderline, text), len(r.links))
+		})
+		part = boldPattern.ReplaceAllStringFunc(part, func(m string) string {
+			return r.style(ansiBold, boldPattern.FindStringSubmatch(m)[2])
+		})
+		part = italicPattern.ReplaceAllStringFunc(part, func(m string) string {
+			sub := italicPattern.FindStringSubmatch(m)
+			return sub[1] + r.style(ansiItalic, sub[2]) + sub[3]
+		})
+		part = strikePattern.ReplaceAllString(part, "$1")
+		if i == 0 {
+			part = breakingMarker.ReplaceAllStringFunc(part, func(m string) string {
+				return r.style(ansiBold+ansiRed, m)
+			})
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+// style wraps s in an ANSI style unless colors are disabled.
+func (r *notesRenderer) style(style, s string) string {
+	if r.opts.NoColor || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// finish appends the link footnotes and returns the rendered text.
+func (r *notesRenderer) finish() string {
+	if len(r.links) > 0 {
+		r.block()
+		for i, link := range r.links {
+			r.line(r.style(ansiDim, fmt.Sprintf("[%d] %s", i+1, link)))
+		}
+	}
+	return strings.TrimRight(r.b.String(), "\n") + "\n"
+}
+
+// visibleWidth returns the number of columns s occupies, ignoring ANSI sequences.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
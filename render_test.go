@@ -0,0 +1,73 @@
+package gitearelease
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderReleaseNotes_NoColor(t *testing.T) {
+	rel := Release{
+		TagName:     "v1.2.0",
+		Name:        "Spring",
+		PublishedAt: "2024-03-01T00:00:00Z",
+		Body:        "## Features\r\n\r\n- Add **parallel** downloads with a [retry policy](https://example.com/retry) that backs off\r\n- Read `GITEA_TOKEN`\r\n\r\n```\r\ngo   install\r\n```\r\n\r\nBREAKING CHANGE: drop Go 1.20",
+	}
+
+	got := RenderReleaseNotes(rel, RenderOptions{Width: 40, NoColor: true, BreakingFirst: true})
+	expected := `v1.2.0 - Spring (2024-03-01)
+============================
+
+⚠ Breaking changes
+  • drop Go 1.20
+
+Features
+--------
+
+  • Add parallel downloads with a retry
+    policy[1] that backs off
+  • Read ` + "`GITEA_TOKEN`" + `
+
+    go   install
+
+BREAKING CHANGE: drop Go 1.20
+
+[1] https://example.com/retry
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("Expected no ANSI sequences in no-color mode, got %q", got)
+	}
+}
+
+func TestRenderMarkdown_Color(t *testing.T) {
+	got := RenderMarkdown("# Title\n\n*new* `flag` for snake_case_names", RenderOptions{})
+	expected := "\x1b[1m\x1b[4mTitle\x1b[0m\n\n\x1b[3mnew\x1b[0m \x1b[36mflag\x1b[0m for snake_case_names\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestRenderMarkdown_WrapIgnoresANSI(t *testing.T) {
+	got := RenderMarkdown("**one** **two** **three** **four** **five** **six**", RenderOptions{Width: 20})
+	for _, line := range strings.Split(strings.TrimRight(got, "\n"), "\n") {
+		if w := visibleWidth(line); w > 20 {
+			t.Errorf("Expected lines of at most 20 columns, got %d in %q", w, line)
+		}
+	}
+	if lines := strings.Count(got, "\n"); lines != 2 {
+		t.Errorf("Expected 2 lines, got %d: %q", lines, got)
+	}
+}
+
+func TestBreakingChanges(t *testing.T) {
+	rel := Release{Body: "## BREAKING CHANGES\n\n- Rename `Own`\n\n## Features\n\n- New flag\n\nBREAKING CHANGE: drop Go 1.20"}
+	got := BreakingChanges(rel)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 breaking changes, got %v", got)
+	}
+	if !strings.Contains(strings.Join(got, "|"), "drop Go 1.20") {
+		t.Errorf("Expected the BREAKING CHANGE line to be extracted, got %v", got)
+	}
+}
@@ -0,0 +1,396 @@
+package gitearelease
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// SchemaVersion is the version of the Document format. New optional fields
+// keep the version; it changes only when a field is removed or changes meaning.
+const SchemaVersion = 1
+
+// SchemaID identifies the JSON Schema that describes Document.
+const SchemaID = "https://github.com/earentir/gitearelease/blob/main/schema/document.v1.schema.json"
+
+//go:embed schema/document.v1.schema.json
+var documentSchema []byte
+
+// DocumentSchema returns the JSON Schema document describing Document.
+func DocumentSchema() []byte {
+	return append([]byte(nil), documentSchema...)
+}
+
+// Document is the stable, provider-neutral JSON form of releases or
+// repositories. Unlike Release and Repository, whose JSON mirrors Gitea's API,
+// it only holds fields every provider can fill and omits the ones a provider
+// does not report, so a missing download_count means "unknown", not zero.
+// Capabilities lists what the provider reports; see the Capability constants.
+type Document struct {
+	Schema        string               `json:"$schema"`
+	SchemaVersion int                  `json:"schema_version"`
+	Provider      string               `json:"provider"`
+	Capabilities  []string             `json:"capabilities"`
+	Releases      []DocumentRelease    `json:"releases,omitempty"`
+	Repositories  []DocumentRepository `json:"repositories,omitempty"`
+}
+
+// DocumentRelease is a release in a Document.
+type DocumentRelease struct {
+	ID              int             `json:"id,omitempty"`
+	Tag             string          `json:"tag"`
+	Name            string          `json:"name,omitempty"`
+	Body            string          `json:"body,omitempty"`
+	Draft           bool            `json:"draft"`
+	Prerelease      bool            `json:"prerelease"`
+	CreatedAt       string          `json:"created_at,omitempty"`
+	PublishedAt     string          `json:"published_at,omitempty"`
+	TargetCommitish string          `json:"target_commitish,omitempty"`
+	CommitSHA       string          `json:"commit_sha,omitempty"`
+	APIURL          string          `json:"api_url,omitempty"`
+	HTMLURL         string          `json:"html_url,omitempty"`
+	TarballURL      string          `json:"tarball_url,omitempty"`
+	ZipballURL      string          `json:"zipball_url,omitempty"`
+	Author          *DocumentAuthor `json:"author,omitempty"`
+	Assets          []DocumentAsset `json:"assets"`
+}
+
+// DocumentAuthor is the author of a DocumentRelease.
+type DocumentAuthor struct {
+	Login    string `json:"login"`
+	FullName string `json:"full_name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// DocumentAsset is a release asset in a Document. Size and DownloadCount are
+// nil when the provider does not report them.
+type DocumentAsset struct {
+	ID            int    `json:"id,omitempty"`
+	Name          string `json:"name"`
+	DownloadURL   string `json:"download_url,omitempty"`
+	APIURL        string `json:"api_url,omitempty"`
+	Size          *int64 `json:"size,omitempty"`
+	DownloadCount *int   `json:"download_count,omitempty"`
+	ContentType   string `json:"content_type,omitempty"`
+	CreatedAt     string `json:"created_at,omitempty"`
+	UUID          string `json:"uuid,omitempty"`
+}
+
+// DocumentRepository is a repository in a Document. Visibility is "public",
+// "private" or "internal"; ReleaseCount is nil when it was not counted.
+type DocumentRepository struct {
+	ID            int                  `json:"id"`
+	Name          string               `json:"name"`
+	FullName      string               `json:"full_name"`
+	Owner         DocumentOwner        `json:"owner"`
+	Description   string               `json:"description,omitempty"`
+	Visibility    string               `json:"visibility"`
+	Fork          bool                 `json:"fork"`
+	Archived      bool                 `json:"archived"`
+	Language      string               `json:"language,omitempty"`
+	Topics        []string             `json:"topics,omitempty"`
+	DefaultBranch string               `json:"default_branch,omitempty"`
+	HTMLURL       string               `json:"html_url,omitempty"`
+	CloneURL      string               `json:"clone_url,omitempty"`
+	SSHURL        string               `json:"ssh_url,omitempty"`
+	Stars         int                  `json:"stars"`
+	Forks         int                  `json:"forks"`
+	OpenIssues    int                  `json:"open_issues"`
+	ReleaseCount  *int                 `json:"release_count,omitempty"`
+	SizeKB        int                  `json:"size_kb,omitempty"`
+	CreatedAt     string               `json:"created_at,omitempty"`
+	UpdatedAt     string               `json:"updated_at,omitempty"`
+	Permissions   *DocumentPermissions `json:"permissions,omitempty"`
+}
+
+// DocumentOwner is the owner of a DocumentRepository.
+type DocumentOwner struct {
+	ID        int    `json:"id,omitempty"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// DocumentPermissions are the token owner's permissions on a DocumentRepository.
+type DocumentPermissions struct {
+	Admin bool `json:"admin"`
+	Push  bool `json:"push"`
+	Pull  bool `json:"pull"`
+}
+
+// documentCapabilities returns the capabilities of providerType plus extra,
+// the ones unlocked by request options such as EnrichAssets.
+func documentCapabilities(providerType providers.ProviderType, baseURL string, extra Capabilities) Capabilities {
+	return providerCapabilities(providers.GetProvider(providerType, baseURL)) | extra
+}
+
+// documentProvider returns the provider name recorded in a Document.
+func documentProvider(providerType providers.ProviderType) string {
+	if providerType == providers.ProviderGitHubEnterprise {
+		return string(providers.ProviderGitHub)
+	}
+	return string(providerType)
+}
+
+// NewReleaseDocument returns the Document for releases fetched with r.
+func NewReleaseDocument(r ReleaseToFetch, releases []Release) Document {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	var extra Capabilities
+	if r.EnrichAssets && providerType == providers.ProviderGitLab {
+		extra = CapabilityAssetSize | CapabilityAssetCreatedAt | CapabilityAssetContentType
+	}
+	caps := documentCapabilities(providerType, r.BaseURL, extra)
+
+	doc := Document{
+		Schema:        SchemaID,
+		SchemaVersion: SchemaVersion,
+		Provider:      documentProvider(providerType),
+		Capabilities:  caps.Names(),
+		Releases:      make([]DocumentRelease, len(releases)),
+	}
+	for i, rel := range releases {
+		doc.Releases[i] = newDocumentRelease(rel, caps)
+	}
+	return doc
+}
+
+// NewRepositoryDocument returns the Document for repositories listed with r.
+func NewRepositoryDocument(r RepositoriesToFetch, repos []Repository) Document {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	var extra Capabilities
+	if r.CountReleases || r.UseGraphQL {
+		extra = CapabilityReleaseCount
+	}
+	caps := documentCapabilities(providerType, r.BaseURL, extra)
+
+	doc := Document{
+		Schema:        SchemaID,
+		SchemaVersion: SchemaVersion,
+		Provider:      documentProvider(providerType),
+		Capabilities:  caps.Names(),
+		Repositories:  make([]DocumentRepository, len(repos)),
+	}
+	for i, repo := range repos {
+		doc.Repositories[i] = newDocumentRepository(repo, caps)
+	}
+	return doc
+}
+
+// ParseDocument decodes a Document, rejecting versions newer than SchemaVersion.
+func ParseDocument(data []byte) (Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > SchemaVersion {
+		return Document{}, fmt.Errorf("parse document: unsupported schema version %d", doc.SchemaVersion)
+	}
+	return doc, nil
+}
+
+// ReleaseList converts the releases of d back to Release values. Fields the
+// provider did not report are left zero and missing from Capabilities and Known.
+func (d Document) ReleaseList() []Release {
+	caps := ParseCapabilities(d.Capabilities)
+	releases := make([]Release, len(d.Releases))
+	for i, dr := range d.Releases {
+		rel := Release{
+			ID:              dr.ID,
+			TagName:         dr.Tag,
+			Name:            dr.Name,
+			Body:            dr.Body,
+			URL:             dr.APIURL,
+			HTMLUrl:         dr.HTMLURL,
+			TarballURL:      dr.TarballURL,
+			ZipballURL:      dr.ZipballURL,
+			Draft:           dr.Draft,
+			Prerelease:      dr.Prerelease,
+			CreatedAt:       dr.CreatedAt,
+			PublishedAt:     dr.PublishedAt,
+			TargetCommitish: dr.TargetCommitish,
+			CommitSHA:       dr.CommitSHA,
+			Assets:          make([]Asset, len(dr.Assets)),
+			Capabilities:    caps,
+		}
+		rel.CreatedTime, _ = ParseTimestamp(dr.CreatedAt)
+		rel.PublishedTime, _ = ParseTimestamp(dr.PublishedAt)
+		if dr.Author != nil {
+			rel.Author = Author{Login: dr.Author.Login, Username: dr.Author.Login, FullName: dr.Author.FullName, Email: dr.Author.Email}
+		}
+		for j, da := range dr.Assets {
+			asset := Asset{
+				ID:                 da.ID,
+				Name:               da.Name,
+				URL:                da.APIURL,
+				BrowserDownloadURL: da.DownloadURL,
+				ContentType:        da.ContentType,
+				CreatedAt:          da.CreatedAt,
+				UUID:               da.UUID,
+				Known:              caps & providers.AssetCapabilities,
+			}
+			asset.CreatedTime, _ = ParseTimestamp(da.CreatedAt)
+			if da.Size != nil {
+				asset.Size = *da.Size
+				asset.Known |= CapabilityAssetSize
+			}
+			if da.DownloadCount != nil {
+				asset.DownloadCount = *da.DownloadCount
+				asset.Known |= CapabilityAssetDownloads
+			}
+			rel.Assets[j] = asset
+		}
+		releases[i] = rel
+	}
+	return releases
+}
+
+// RepositoryList converts the repositories of d back to Repository values.
+func (d Document) RepositoryList() []Repository {
+	caps := ParseCapabilities(d.Capabilities)
+	repos := make([]Repository, len(d.Repositories))
+	for i, dr := range d.Repositories {
+		repo := Repository{
+			ID:              dr.ID,
+			Name:            dr.Name,
+			FullName:        dr.FullName,
+			Description:     dr.Description,
+			Private:         dr.Visibility == "private",
+			Internal:        dr.Visibility == "internal",
+			Fork:            dr.Fork,
+			Archived:        dr.Archived,
+			Language:        dr.Language,
+			Topics:          dr.Topics,
+			DefaultBranch:   dr.DefaultBranch,
+			HTMLURL:         dr.HTMLURL,
+			CloneURL:        dr.CloneURL,
+			SSHURL:          dr.SSHURL,
+			StarsCount:      dr.Stars,
+			ForksCount:      dr.Forks,
+			OpenIssuesCount: dr.OpenIssues,
+			Size:            dr.SizeKB,
+			Capabilities:    caps,
+		}
+		repo.Owner.ID = dr.Owner.ID
+		repo.Owner.Login = dr.Owner.Login
+		repo.Owner.Username = dr.Owner.Login
+		repo.Owner.FullName = dr.Owner.FullName
+		repo.Owner.AvatarURL = dr.Owner.AvatarURL
+		if dr.ReleaseCount != nil {
+			repo.ReleaseCounter = *dr.ReleaseCount
+			repo.HasReleases = *dr.ReleaseCount > 0
+			repo.Capabilities |= CapabilityReleaseCount
+		}
+		repo.CreatedAt, _ = ParseTimestamp(dr.CreatedAt)
+		repo.UpdatedAt, _ = ParseTimestamp(dr.UpdatedAt)
+		if dr.Permissions != nil {
+			repo.Permissions.Admin = dr.Permissions.Admin
+			repo.Permissions.Push = dr.Permissions.Push
+			repo.Permissions.Pull = dr.Permissions.Pull
+		}
+		repos[i] = repo
+	}
+	return repos
+}
+
+// newDocumentRelease converts rel. Asset fields are known when the asset says
+// so or, for releases built by hand, when caps includes them.
+func newDocumentRelease(rel Release, caps Capabilities) DocumentRelease {
+	dr := DocumentRelease{
+		ID:              rel.ID,
+		Tag:             rel.TagName,
+		Name:            rel.Name,
+		Body:            rel.Body,
+		Draft:           rel.Draft,
+		Prerelease:      rel.Prerelease,
+		CreatedAt:       formatTimestamp(rel.CreatedTime, rel.CreatedAt),
+		PublishedAt:     formatTimestamp(rel.PublishedTime, rel.PublishedAt),
+		TargetCommitish: rel.TargetCommitish,
+		CommitSHA:       rel.CommitSHA,
+		APIURL:          rel.URL,
+		HTMLURL:         rel.HTMLUrl,
+		TarballURL:      rel.TarballURL,
+		ZipballURL:      rel.ZipballURL,
+		Assets:          make([]DocumentAsset, len(rel.Assets)),
+	}
+	if login := firstNonEmpty(rel.Author.Login, rel.Author.Username); login != "" {
+		dr.Author = &DocumentAuthor{Login: login, FullName: rel.Author.FullName, Email: rel.Author.Email}
+	}
+
+	for i, a := range rel.Assets {
+		da := DocumentAsset{
+			ID:          a.ID,
+			Name:        a.Name,
+			DownloadURL: a.BrowserDownloadURL,
+			APIURL:      a.URL,
+			ContentType: a.ContentType,
+			CreatedAt:   formatTimestamp(a.CreatedTime, a.CreatedAt),
+			UUID:        a.UUID,
+		}
+		known := a.Known
+		if known == 0 {
+			known = caps & providers.AssetCapabilities
+		}
+		if size := a.Size; size > 0 || known.Has(CapabilityAssetSize) {
+			da.Size = &size
+		}
+		if count := a.DownloadCount; known.Has(CapabilityAssetDownloads) {
+			da.DownloadCount = &count
+		}
+		dr.Assets[i] = da
+	}
+	return dr
+}
+
+func newDocumentRepository(repo Repository, caps Capabilities) DocumentRepository {
+	dr := DocumentRepository{
+		ID:       repo.ID,
+		Name:     repo.Name,
+		FullName: repo.FullName,
+		Owner: DocumentOwner{
+			ID:        repo.Owner.ID,
+			Login:     firstNonEmpty(repo.Owner.Login, repo.Owner.Username),
+			FullName:  repo.Owner.FullName,
+			AvatarURL: repo.Owner.AvatarURL,
+		},
+		Description:   repo.Description,
+		Visibility:    "public",
+		Fork:          repo.Fork,
+		Archived:      repo.Archived,
+		Language:      repo.Language,
+		Topics:        repo.Topics,
+		DefaultBranch: repo.DefaultBranch,
+		HTMLURL:       repo.HTMLURL,
+		CloneURL:      repo.CloneURL,
+		SSHURL:        repo.SSHURL,
+		Stars:         repo.StarsCount,
+		Forks:         repo.ForksCount,
+		OpenIssues:    repo.OpenIssuesCount,
+		SizeKB:        repo.Size,
+	}
+	switch {
+	case repo.Private:
+		dr.Visibility = "private"
+	case repo.Internal:
+		dr.Visibility = "internal"
+	}
+	if count := repo.ReleaseCounter; (repo.Capabilities | caps).Has(CapabilityReleaseCount) {
+		dr.ReleaseCount = &count
+	}
+	dr.CreatedAt = formatTimestamp(repo.CreatedAt, "")
+	dr.UpdatedAt = formatTimestamp(repo.UpdatedAt, "")
+	if p := repo.Permissions; p.Admin || p.Push || p.Pull {
+		dr.Permissions = &DocumentPermissions{Admin: p.Admin, Push: p.Push, Pull: p.Pull}
+	}
+	return dr
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,282 @@
+package gitearelease
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+func testDocumentReleases() []Release {
+	caps := providers.NewGitHubProvider().Capabilities()
+	return []Release{{
+		ID:              1001,
+		TagName:         "v1.4.0",
+		Name:            "Spring",
+		Body:            "## Features\n\n- Schema",
+		URL:             "https://api.github.com/repos/earentir/tool/releases/1001",
+		HTMLUrl:         "https://github.com/earentir/tool/releases/tag/v1.4.0",
+		TarballURL:      "https://api.github.com/repos/earentir/tool/tarball/v1.4.0",
+		ZipballURL:      "https://api.github.com/repos/earentir/tool/zipball/v1.4.0",
+		Prerelease:      true,
+		CreatedAt:       "2024-05-01T10:00:00Z",
+		PublishedAt:     "2024-05-01T11:00:00Z",
+		CreatedTime:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		PublishedTime:   time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC),
+		TargetCommitish: "main",
+		Author:          Author{Login: "earentir", Username: "earentir", FullName: "Earentir"},
+		Assets: []Asset{
+			{ID: 77, Name: "tool.tar.gz", URL: "https://api.github.com/repos/earentir/tool/releases/assets/77", BrowserDownloadURL: "https://github.com/earentir/tool/releases/download/v1.4.0/tool.tar.gz", Size: 2048, DownloadCount: 0, ContentType: "application/gzip", CreatedAt: "2024-05-01T10:30:00Z",
+				CreatedTime: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), Known: caps & providers.AssetCapabilities},
+		},
+		Capabilities: caps,
+	}}
+}
+
+func TestDocument_ReleaseRoundTrip(t *testing.T) {
+	releases := testDocumentReleases()
+	doc := NewReleaseDocument(ReleaseToFetch{BaseURL: "https://api.github.com"}, releases)
+	if doc.Provider != "github" || doc.SchemaVersion != SchemaVersion || doc.Schema != SchemaID {
+		t.Errorf("Expected a github v%d document, got %s v%d", SchemaVersion, doc.Provider, doc.SchemaVersion)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(string(data), `"download_count":0`) {
+		t.Errorf("Expected GitHub's zero download count to be reported, got %s", data)
+	}
+
+	parsed, err := ParseDocument(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(parsed, doc) {
+		t.Errorf("Expected the parsed document to equal the original\n%+v\n%+v", parsed, doc)
+	}
+	if got := parsed.ReleaseList(); !reflect.DeepEqual(got, releases) {
+		t.Errorf("Expected the releases back\n%+v\ngot\n%+v", releases, got)
+	}
+
+	again, _ := json.Marshal(parsed)
+	if string(again) != string(data) {
+		t.Errorf("Expected stable JSON\n%s\ngot\n%s", data, again)
+	}
+}
+
+func TestDocument_RepositoryRoundTrip(t *testing.T) {
+	repo := Repository{
+		ID:              12,
+		Name:            "tool",
+		FullName:        "earentir/tool",
+		Description:     "A tool",
+		Internal:        true,
+		Language:        "Go",
+		Topics:          []string{"cli", "release"},
+		DefaultBranch:   "main",
+		HTMLURL:         "https://gitea.com/earentir/tool",
+		CloneURL:        "https://gitea.com/earentir/tool.git",
+		SSHURL:          "git@gitea.com:earentir/tool.git",
+		StarsCount:      3,
+		ForksCount:      1,
+		ReleaseCounter:  5,
+		HasReleases:     true,
+		Size:            120,
+		CreatedAt:       time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:       time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		OpenIssuesCount: 2,
+		Capabilities:    providers.NewGiteaProvider().Capabilities(),
+	}
+	repo.Owner.ID = 1
+	repo.Owner.Login = "earentir"
+	repo.Owner.Username = "earentir"
+	repo.Permissions.Pull = true
+
+	doc := NewRepositoryDocument(RepositoriesToFetch{BaseURL: "https://gitea.com"}, []Repository{repo})
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	parsed, err := ParseDocument(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := parsed.RepositoryList(); !reflect.DeepEqual(got, []Repository{repo}) {
+		t.Errorf("Expected the repository back\n%+v\ngot\n%+v", repo, got)
+	}
+	if parsed.Repositories[0].Visibility != "internal" || parsed.Repositories[0].ReleaseCount == nil {
+		t.Errorf("Expected an internal repository with a release count, got %+v", parsed.Repositories[0])
+	}
+
+	// GitHub release counts are unknown unless counted
+	repo.Capabilities = providers.NewGitHubProvider().Capabilities()
+	doc = NewRepositoryDocument(RepositoriesToFetch{BaseURL: "https://api.github.com"}, []Repository{repo})
+	if doc.Repositories[0].ReleaseCount != nil {
+		t.Errorf("Expected no GitHub release count, got %d", *doc.Repositories[0].ReleaseCount)
+	}
+}
+
+func TestDocument_OmitsUnknownFields(t *testing.T) {
+	releases := []Release{{TagName: "v1.0.0", Assets: []Asset{{Name: "tool.zip", BrowserDownloadURL: "https://gitlab.com/x"}}}}
+	doc := NewReleaseDocument(ReleaseToFetch{BaseURL: "https://gitlab.com"}, releases)
+	data, _ := json.Marshal(doc)
+	for _, field := range []string{`"size"`, `"download_count"`, `"author"`, `"uuid"`} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("Expected %s to be omitted for GitLab, got %s", field, data)
+		}
+	}
+	if ParseCapabilities(doc.Capabilities).Has(CapabilityAssetDownloads) {
+		t.Errorf("Expected GitLab not to report download counts, got %v", doc.Capabilities)
+	}
+
+	enriched := NewReleaseDocument(ReleaseToFetch{BaseURL: "https://gitlab.com", EnrichAssets: true}, releases)
+	if !ParseCapabilities(enriched.Capabilities).Has(CapabilityAssetSize) || enriched.Releases[0].Assets[0].Size == nil {
+		t.Errorf("Expected enriched GitLab assets to report their size, got %+v", enriched)
+	}
+}
+
+func TestDocument_NormalizesTimestamps(t *testing.T) {
+	releases := []Release{{
+		TagName:     "v1.0.0",
+		CreatedAt:   "2024-05-01 10:00:00 UTC",
+		PublishedAt: "2024-05-01T12:30:00.5+02:00",
+		Assets:      []Asset{{Name: "tool.zip", CreatedAt: "Wed, 01 May 2024 10:45:00 GMT"}, {Name: "tool.tar.gz", CreatedAt: "yesterday"}},
+	}}
+	doc := NewReleaseDocument(ReleaseToFetch{BaseURL: "https://gitlab.com"}, releases)
+
+	rel := doc.Releases[0]
+	if rel.CreatedAt != "2024-05-01T10:00:00Z" || rel.PublishedAt != "2024-05-01T10:30:00.5Z" {
+		t.Errorf("Expected RFC 3339 UTC release times, got %q and %q", rel.CreatedAt, rel.PublishedAt)
+	}
+	if rel.Assets[0].CreatedAt != "2024-05-01T10:45:00Z" {
+		t.Errorf("Expected an RFC 3339 UTC asset time, got %q", rel.Assets[0].CreatedAt)
+	}
+	if rel.Assets[1].CreatedAt != "" {
+		t.Errorf("Expected an unparseable time to be omitted, got %q", rel.Assets[1].CreatedAt)
+	}
+}
+
+func TestParseDocument_Version(t *testing.T) {
+	if _, err := ParseDocument([]byte(`{"schema_version": 2, "provider": "gitea", "capabilities": []}`)); err == nil {
+		t.Error("Expected an error for a newer schema version")
+	}
+	if _, err := ParseDocument([]byte(`{"provider": "gitea"}`)); err == nil {
+		t.Error("Expected an error for a document without a schema version")
+	}
+}
+
+func TestDocument_MatchesSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(DocumentSchema(), &schema); err != nil {
+		t.Fatalf("Expected a valid JSON Schema document, got %v", err)
+	}
+	if schema["$id"] != SchemaID {
+		t.Errorf("Expected $id %s, got %v", SchemaID, schema["$id"])
+	}
+
+	repo := Repository{ID: 1, Name: "tool", FullName: "me/tool", Topics: []string{"cli"}, CreatedAt: time.Now()}
+	repo.Owner.Login = "me"
+	repo.Permissions.Admin = true
+	docs := []Document{
+		NewReleaseDocument(ReleaseToFetch{BaseURL: "https://api.github.com"}, testDocumentReleases()),
+		NewReleaseDocument(ReleaseToFetch{BaseURL: "https://gitlab.com"}, testDocumentReleases()),
+		NewRepositoryDocument(RepositoriesToFetch{BaseURL: "https://gitea.com", CountReleases: true}, []Repository{repo}),
+	}
+	for _, doc := range docs {
+		data, _ := json.Marshal(doc)
+		var instance interface{}
+		json.Unmarshal(data, &instance)
+		if err := validateSchema(schema, schema, instance, "$"); err != nil {
+			t.Errorf("Expected the %s document to match the schema, got %v", doc.Provider, err)
+		}
+	}
+
+	// The validator itself rejects fields the schema does not declare
+	var instance interface{}
+	json.Unmarshal([]byte(`{"$schema": "x", "schema_version": 1, "provider": "gitea", "capabilities": [], "extra": 1}`), &instance)
+	if err := validateSchema(schema, schema, instance, "$"); err == nil {
+		t.Error("Expected an undeclared field to fail validation")
+	}
+}
+
+// validateSchema checks instance against the subset of JSON Schema the
+// document schema uses: $ref, type, const, enum, required, properties,
+// additionalProperties: false and items.
+func validateSchema(root, schema map[string]interface{}, instance interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, _ := root["$defs"].(map[string]interface{})[name].(map[string]interface{})
+		if def == nil {
+			return fmt.Errorf("%s: unknown $ref %s", path, ref)
+		}
+		return validateSchema(root, def, instance, path)
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, instance) {
+		return fmt.Errorf("%s: expected %v, got %v", path, c, instance)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			found = found || reflect.DeepEqual(v, instance)
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, instance, enum)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := instance.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, name := range schema["required"].([]interface{}) {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		for name, value := range obj {
+			prop, ok := props[name].(map[string]interface{})
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: undeclared property %s", path, name)
+				}
+				continue
+			}
+			if err := validateSchema(root, prop, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := instance.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array", path)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range arr {
+			if err := validateSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := instance.(string); !ok {
+			return fmt.Errorf("%s: expected a string", path)
+		}
+	case "boolean":
+		if _, ok := instance.(bool); !ok {
+			return fmt.Errorf("%s: expected a boolean", path)
+		}
+	case "integer":
+		n, ok := instance.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: expected an integer", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// SearchRepositories searches repositories with the provider's search endpoint
+// (Gitea /repos/search, GitHub /search/repositories, GitLab /projects?search=),
+// following pagination until the results run out or MaxResults repositories
+// have passed the filters. Results are filtered and counted like GetRepositories.
+func SearchRepositories(s RepositorySearch) ([]Repository, error) {
+	providerType := resolveProviderType(s.Provider, s.BaseURL)
+	baseURL := normalizeBaseURL(s.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+	searcher, ok := provider.(providers.RepositorySearcher)
+	if !ok {
+		return nil, fmt.Errorf("provider cannot search repositories: %w", errors.ErrUnsupported)
+	}
+
+	owner, isGroup := searchOwner(s)
+	apiURL := searcher.GetSearchRepositoriesURL(baseURL, providers.SearchOptions{
+		Query:        s.Query,
+		Topic:        s.Topic,
+		Language:     s.Language,
+		Sort:         s.Sort,
+		Order:        s.Order,
+		Owner:        owner,
+		OwnerIsGroup: isGroup,
+	})
+
+	headers := requestHeaders(provider, s.Token)
+	repos := []Repository{}
+	err := eachPage(withPageSize(apiURL, providerType), headers, maxPages, func(apiData []byte) (bool, error) {
+		providerRepos, err := searcher.NormalizeSearchRepositories(apiData)
+		if err != nil {
+			return false, err
+		}
+		for _, pr := range providerRepos {
+			repo := convertProviderRepository(pr)
+			if !matchesRepositoryFilters(repo, s.RepositoriesToFetch) {
+				continue
+			}
+			if providerType == providers.ProviderGitea && !matchesGiteaSearch(repo, s) {
+				continue
+			}
+			repos = append(repos, repo)
+			if s.MaxResults > 0 && len(repos) == s.MaxResults {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.CountReleases && providerType != providers.ProviderGitea {
+		if err := countReleases(repos, provider, baseURL, headers, s.Concurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	return filterWithReleases(repos, s.WithReleases), nil
+}
+
+// searchOwner returns the user or group a search is restricted to, if any,
+// and whether it is an organization or group.
+func searchOwner(s RepositorySearch) (owner string, isGroup bool) {
+	if s.Group != "" {
+		return s.Group, true
+	}
+	if s.Owner == OwnerAuthenticated {
+		return "", false
+	}
+	return s.User, s.Owner == OwnerOrg
+}
+
+// matchesGiteaSearch applies the search terms Gitea cannot express in one
+// request: the query when searching by topic, the language and the owner.
+func matchesGiteaSearch(repo Repository, s RepositorySearch) bool {
+	if s.Topic != "" && s.Query != "" {
+		query := strings.ToLower(s.Query)
+		if !strings.Contains(strings.ToLower(repo.Name), query) && !strings.Contains(strings.ToLower(repo.Description), query) {
+			return false
+		}
+	}
+	if s.Language != "" && !strings.EqualFold(repo.Language, s.Language) {
+		return false
+	}
+	if owner, _ := searchOwner(s); owner != "" && !strings.EqualFold(repo.Owner.Login, owner) {
+		return false
+	}
+	return true
+}
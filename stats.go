@@ -0,0 +1,321 @@
+package gitearelease
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statsStoreVersion is the format version of the StatsStore file.
+const statsStoreVersion = 1
+
+// GetDownloadStats fetches every release of each target and summarizes its
+// download counts. Targets that fail are left out of the result and their
+// errors joined into the returned error, so a partial snapshot is still usable.
+func GetDownloadStats(targets ...ReleaseToFetch) (DownloadStats, error) {
+	repos := make([]RepositoryDownloads, len(targets))
+	errs := make([]error, len(targets))
+	forEachLimit(len(targets), defaultEnrichConcurrency, func(i int) {
+		providerType := resolveProviderType(targets[i].Provider, targets[i].BaseURL)
+		user, repo := repoCoordinates(targets[i], providerType)
+		name := strings.Trim(user+"/"+repo, "/")
+
+		releases, err := fetchAllReleases(targets[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("download stats of %q: %w", name, err)
+			return
+		}
+		repos[i] = SummarizeDownloads(name, releases)
+		repos[i].Provider = documentProvider(providerType)
+	})
+
+	stats := DownloadStats{Time: time.Now().UTC(), Repositories: []RepositoryDownloads{}}
+	for i, repo := range repos {
+		if errs[i] == nil {
+			stats.Repositories = append(stats.Repositories, repo)
+		}
+	}
+	return stats, errors.Join(errs...)
+}
+
+// SummarizeDownloads aggregates the download counts of releases per release,
+// per asset and per platform. Releases keep their order; platforms are sorted
+// by downloads, most first.
+func SummarizeDownloads(repository string, releases []Release) RepositoryDownloads {
+	summary := RepositoryDownloads{
+		Repository: repository,
+		Releases:   make([]ReleaseDownloads, len(releases)),
+		Platforms:  []PlatformDownloads{},
+	}
+	platforms := map[[2]string]int{}
+
+	for i, rel := range releases {
+		rd := ReleaseDownloads{
+			Tag:         rel.TagName,
+			PublishedAt: rel.PublishedAt,
+			Prerelease:  rel.Prerelease,
+			Assets:      make([]AssetDownloads, len(rel.Assets)),
+		}
+		for j, a := range rel.Assets {
+			goos, goarch := AssetPlatform(a.Name)
+			ad := AssetDownloads{
+				Name:      a.Name,
+				OS:        goos,
+				Arch:      goarch,
+				Downloads: a.DownloadCount,
+				Known:     downloadsKnown(a),
+			}
+			rd.Assets[j] = ad
+			if !ad.Known {
+				rd.UnknownAssets++
+				continue
+			}
+			rd.Downloads += ad.Downloads
+			platforms[[2]string{goos, goarch}] += ad.Downloads
+		}
+		summary.Releases[i] = rd
+		summary.Downloads += rd.Downloads
+		summary.UnknownAssets += rd.UnknownAssets
+	}
+
+	for p, downloads := range platforms {
+		summary.Platforms = append(summary.Platforms, PlatformDownloads{OS: p[0], Arch: p[1], Downloads: downloads})
+	}
+	sort.Slice(summary.Platforms, func(i, j int) bool {
+		a, b := summary.Platforms[i], summary.Platforms[j]
+		if a.Downloads != b.Downloads {
+			return a.Downloads > b.Downloads
+		}
+		return a.OS+"/"+a.Arch < b.OS+"/"+b.Arch
+	})
+	return summary
+}
+
+// downloadsKnown reports whether the download count of a is reported by the
+// provider. Assets built by hand or read back from JSON carry no Known mask;
+// their count is trusted when it is not zero.
+func downloadsKnown(a Asset) bool {
+	if a.Known == 0 {
+		return a.DownloadCount > 0
+	}
+	return a.Known.Has(CapabilityAssetDownloads)
+}
+
+// WriteJSON writes s as indented JSON.
+func (s DownloadStats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV writes one row per asset. The downloads column is empty when the
+// provider does not report the asset's download count.
+func (s DownloadStats) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "repository", "provider", "release", "published_at", "asset", "os", "arch", "downloads"})
+	when := s.Time.UTC().Format(time.RFC3339)
+	for _, repo := range s.Repositories {
+		for _, rel := range repo.Releases {
+			for _, a := range rel.Assets {
+				downloads := ""
+				if a.Known {
+					downloads = strconv.Itoa(a.Downloads)
+				}
+				cw.Write([]string{when, repo.Repository, repo.Provider, rel.Tag, rel.PublishedAt, a.Name, a.OS, a.Arch, downloads})
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePrometheus writes s in the Prometheus text exposition format, as
+// gauges per asset, release, platform and repository, each labelled with the
+// repository and provider. Assets with an unknown download count are left out,
+// as are releases and repositories none of whose assets has a known count.
+func (s DownloadStats) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+
+	promMetric(&b, "gitearelease_asset_downloads", "Downloads of a release asset.")
+	for _, repo := range s.Repositories {
+		for _, rel := range repo.Releases {
+			for _, a := range rel.Assets {
+				if a.Known {
+					promSample(&b, "gitearelease_asset_downloads", a.Downloads, "repository", repo.Repository, "provider", repo.Provider,
+						"release", rel.Tag, "asset", a.Name, "os", a.OS, "arch", a.Arch)
+				}
+			}
+		}
+	}
+
+	promMetric(&b, "gitearelease_release_downloads", "Downloads of all assets of a release.")
+	for _, repo := range s.Repositories {
+		for _, rel := range repo.Releases {
+			if rel.UnknownAssets > 0 && rel.UnknownAssets == len(rel.Assets) {
+				continue
+			}
+			promSample(&b, "gitearelease_release_downloads", rel.Downloads, "repository", repo.Repository, "provider", repo.Provider, "release", rel.Tag)
+		}
+	}
+
+	promMetric(&b, "gitearelease_platform_downloads", "Downloads of all assets built for a platform.")
+	for _, repo := range s.Repositories {
+		for _, p := range repo.Platforms {
+			promSample(&b, "gitearelease_platform_downloads", p.Downloads, "repository", repo.Repository, "provider", repo.Provider, "os", p.OS, "arch", p.Arch)
+		}
+	}
+
+	promMetric(&b, "gitearelease_repository_downloads", "Downloads of all release assets of a repository.")
+	for _, repo := range s.Repositories {
+		if repo.UnknownAssets > 0 && repo.UnknownAssets == repo.assetCount() {
+			continue
+		}
+		promSample(&b, "gitearelease_repository_downloads", repo.Downloads, "repository", repo.Repository, "provider", repo.Provider)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// assetCount returns the number of assets across all of r's releases.
+func (r RepositoryDownloads) assetCount() int {
+	n := 0
+	for _, rel := range r.Releases {
+		n += len(rel.Assets)
+	}
+	return n
+}
+
+// promMetric writes the HELP and TYPE lines of a gauge.
+func promMetric(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// promSample writes one gauge sample; labels are name/value pairs.
+func promSample(b *strings.Builder, name string, value int, labels ...string) {
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, `%s="%s"`, labels[i], promEscaper.Replace(labels[i+1]))
+	}
+	fmt.Fprintf(b, "} %d\n", value)
+}
+
+// promEscaper escapes Prometheus label values.
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// StatsStore keeps download snapshots in a JSON file, so that running
+// GetDownloadStats periodically builds a download history.
+type StatsStore struct {
+	path      string
+	snapshots []DownloadStats
+}
+
+// statsFile is the on-disk format of a StatsStore.
+type statsFile struct {
+	Version   int             `json:"version"`
+	Snapshots []DownloadStats `json:"snapshots"`
+}
+
+// OpenStatsStore loads the store at path; a missing file is an empty store.
+func OpenStatsStore(path string) (*StatsStore, error) {
+	store := &StatsStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read stats store: %w", err)
+	}
+
+	var file statsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse stats store %q: %w", path, err)
+	}
+	if file.Version != statsStoreVersion {
+		return nil, fmt.Errorf("stats store %q: unsupported version %d", path, file.Version)
+	}
+	store.snapshots = file.Snapshots
+	return store, nil
+}
+
+// Snapshots returns the recorded snapshots, oldest first.
+func (s *StatsStore) Snapshots() []DownloadStats {
+	return append([]DownloadStats(nil), s.snapshots...)
+}
+
+// Record appends stats to the store and writes the file atomically.
+func (s *StatsStore) Record(stats DownloadStats) error {
+	s.snapshots = append(s.snapshots, stats)
+	data, err := json.Marshal(statsFile{Version: statsStoreVersion, Snapshots: s.snapshots})
+	if err != nil {
+		return fmt.Errorf("encode stats store: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("write stats store: %w", err)
+	}
+	return nil
+}
+
+// Trend returns the download count of every asset with a known count in every
+// snapshot. Delta is the change since the previous snapshot that included the
+// asset; it is 0 in the first snapshot and the full count for an asset that
+// appeared later.
+func (s *StatsStore) Trend() DownloadTrends {
+	trend := DownloadTrends{}
+	previous := map[[3]string]int{}
+	for i, snapshot := range s.snapshots {
+		for _, repo := range snapshot.Repositories {
+			for _, rel := range repo.Releases {
+				for _, a := range rel.Assets {
+					if !a.Known {
+						continue
+					}
+					key := [3]string{repo.Repository, rel.Tag, a.Name}
+					delta := a.Downloads - previous[key]
+					if _, seen := previous[key]; !seen && i == 0 {
+						delta = 0
+					}
+					previous[key] = a.Downloads
+					trend = append(trend, DownloadTrend{
+						Time:       snapshot.Time,
+						Repository: repo.Repository,
+						Tag:        rel.Tag,
+						Asset:      a.Name,
+						Downloads:  a.Downloads,
+						Delta:      delta,
+					})
+				}
+			}
+		}
+	}
+	return trend
+}
+
+// WriteJSON writes t as indented JSON.
+func (t DownloadTrends) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// WriteCSV writes one row per asset and snapshot.
+func (t DownloadTrends) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "repository", "release", "asset", "downloads", "delta"})
+	for _, row := range t {
+		cw.Write([]string{row.Time.UTC().Format(time.RFC3339), row.Repository, row.Tag, row.Asset, strconv.Itoa(row.Downloads), strconv.Itoa(row.Delta)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,163 @@
+package gitearelease
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetDownloadStats(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": 2, "tag_name": "v1.1.0", "published_at": "2024-06-01T00:00:00Z", "assets": [
+				{"id": 21, "name": "tool_linux_amd64.tar.gz", "download_count": 30},
+				{"id": 22, "name": "tool_darwin_arm64.tar.gz", "download_count": 0},
+				{"id": 23, "name": "checksums.txt", "download_count": 4}]},
+			{"id": 1, "tag_name": "v1.0.0", "published_at": "2024-01-01T00:00:00Z", "assets": [
+				{"id": 11, "name": "tool_Linux_x86_64.tar.gz", "download_count": 12}]}]`))
+	}))
+	defer github.Close()
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"tag_name": "v2.0.0", "assets": {"links": [{"id": 1, "name": "tool_linux_amd64", "url": "https://example.com/tool"}], "sources": []}}]`))
+	}))
+	defer gitlab.Close()
+
+	stats, err := GetDownloadStats(
+		ReleaseToFetch{BaseURL: github.URL, User: "o", Repo: "tool", Provider: "github"},
+		ReleaseToFetch{BaseURL: gitlab.URL, Project: "group/sub/tool", Provider: "gitlab"},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stats.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(stats.Repositories))
+	}
+
+	gh := stats.Repositories[0]
+	if gh.Repository != "o/tool" || gh.Provider != "github" || gh.Downloads != 46 {
+		t.Errorf("Expected o/tool on github with 46 downloads, got %s on %s with %d", gh.Repository, gh.Provider, gh.Downloads)
+	}
+	if gh.Releases[0].Downloads != 34 || gh.Releases[1].Downloads != 12 {
+		t.Errorf("Expected 34 and 12 downloads per release, got %+v", gh.Releases)
+	}
+	if a := gh.Releases[0].Assets[1]; !a.Known || a.Downloads != 0 || a.OS != "darwin" || a.Arch != "arm64" {
+		t.Errorf("Expected a known zero for the darwin/arm64 asset, got %+v", a)
+	}
+	if p := gh.Platforms[0]; p.OS != "linux" || p.Arch != "amd64" || p.Downloads != 42 {
+		t.Errorf("Expected linux/amd64 first with 42 downloads, got %+v", gh.Platforms)
+	}
+
+	gl := stats.Repositories[1]
+	if gl.Repository != "group/sub/tool" || gl.Downloads != 0 || gl.UnknownAssets != 1 || gl.Releases[0].Assets[0].Known {
+		t.Errorf("Expected GitLab downloads to be unknown, got %+v", gl)
+	}
+}
+
+func TestDownloadStats_Export(t *testing.T) {
+	stats := DownloadStats{
+		Time: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+		Repositories: []RepositoryDownloads{
+			SummarizeDownloads("o/tool", []Release{{TagName: "v1.0.0", Assets: []Asset{
+				{Name: `tool "linux" amd64.tar.gz`, DownloadCount: 7, Known: CapabilityAssetDownloads},
+				{Name: "tool.zip", Known: CapabilityAssetID},
+			}}}),
+			SummarizeDownloads("group/tool", []Release{{TagName: "v2.0.0", Assets: []Asset{
+				{Name: "tool_linux_amd64.tar.gz", Known: CapabilityAssetID},
+			}}}),
+		},
+	}
+	stats.Repositories[1].Provider = "gitlab"
+
+	var csvOut bytes.Buffer
+	if err := stats.WriteCSV(&csvOut); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	wantCSV := "time,repository,provider,release,published_at,asset,os,arch,downloads\n" +
+		"2024-06-02T00:00:00Z,o/tool,,v1.0.0,,\"tool \"\"linux\"\" amd64.tar.gz\",linux,amd64,7\n" +
+		"2024-06-02T00:00:00Z,o/tool,,v1.0.0,,tool.zip,,,\n" +
+		"2024-06-02T00:00:00Z,group/tool,gitlab,v2.0.0,,tool_linux_amd64.tar.gz,linux,amd64,\n"
+	if csvOut.String() != wantCSV {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", wantCSV, csvOut.String())
+	}
+
+	var prom bytes.Buffer
+	if err := stats.WritePrometheus(&prom); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE gitearelease_asset_downloads gauge\n",
+		`gitearelease_asset_downloads{repository="o/tool",provider="",release="v1.0.0",asset="tool \"linux\" amd64.tar.gz",os="linux",arch="amd64"} 7` + "\n",
+		`gitearelease_release_downloads{repository="o/tool",provider="",release="v1.0.0"} 7` + "\n",
+		`gitearelease_platform_downloads{repository="o/tool",provider="",os="linux",arch="amd64"} 7` + "\n",
+		`gitearelease_repository_downloads{repository="o/tool",provider=""} 7` + "\n",
+	} {
+		if !strings.Contains(prom.String(), want) {
+			t.Errorf("Expected Prometheus output to contain %q, got:\n%s", want, prom.String())
+		}
+	}
+	if strings.Contains(prom.String(), "tool.zip") {
+		t.Errorf("Expected the asset with unknown downloads to be left out, got:\n%s", prom.String())
+	}
+	if strings.Contains(prom.String(), "group/tool") {
+		t.Errorf("Expected the repository without known downloads to be left out, got:\n%s", prom.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := stats.WriteJSON(&jsonOut); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"unknown_assets": 1`) {
+		t.Errorf("Expected the unknown asset to be counted, got %s", jsonOut.String())
+	}
+}
+
+func TestStatsStore_Trend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	snapshot := func(day int, counts map[string]int) DownloadStats {
+		var assets []Asset
+		for _, name := range []string{"a.tar.gz", "b.tar.gz"} {
+			if n, ok := counts[name]; ok {
+				assets = append(assets, Asset{Name: name, DownloadCount: n, Known: CapabilityAssetDownloads})
+			}
+		}
+		return DownloadStats{
+			Time:         time.Date(2024, 6, day, 0, 0, 0, 0, time.UTC),
+			Repositories: []RepositoryDownloads{SummarizeDownloads("o/tool", []Release{{TagName: "v1", Assets: assets}})},
+		}
+	}
+
+	store, err := OpenStatsStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error for a missing store, got %v", err)
+	}
+	if err := store.Record(snapshot(1, map[string]int{"a.tar.gz": 10})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	store, err = OpenStatsStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Record(snapshot(2, map[string]int{"a.tar.gz": 15, "b.tar.gz": 3})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(store.Snapshots()) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d", len(store.Snapshots()))
+	}
+
+	var out bytes.Buffer
+	if err := store.Trend().WriteCSV(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := "time,repository,release,asset,downloads,delta\n" +
+		"2024-06-01T00:00:00Z,o/tool,v1,a.tar.gz,10,0\n" +
+		"2024-06-02T00:00:00Z,o/tool,v1,a.tar.gz,15,5\n" +
+		"2024-06-02T00:00:00Z,o/tool,v1,b.tar.gz,3,3\n"
+	if out.String() != want {
+		t.Errorf("Expected trend:\n%s\ngot:\n%s", want, out.String())
+	}
+}
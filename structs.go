@@ -10,6 +10,16 @@ type VersionStrings struct {
 	Latest         string
 	VersionStrings versionstringstruct
 	VersionOptions versionoptionsstruct
+
+	// OwnCommit and LatestCommit are optional full or abbreviated commit SHAs of the
+	// running build and the latest release. When both are set they decide whether an
+	// equal version is a verified build, instead of hex suffixes on Own and Latest.
+	OwnCommit    string
+	LatestCommit string
+
+	// OwnModified marks a build from a modified working tree (vcs.modified=true),
+	// which CompareVersionsHelper reports with the Dirty message.
+	OwnModified bool
 }
 
 // versionstringstruct holds the messages for version comparison outcomes.
@@ -21,6 +31,7 @@ type versionstringstruct struct {
 	UpgradeURL string
 	Unverified string
 	Rerelease  string
+	Dirty      string
 }
 
 type versionoptionsstruct struct {
@@ -31,23 +42,290 @@ type versionoptionsstruct struct {
 }
 
 // ReleaseToFetch represents which release(s) to fetch from a repository.
-// Provider can be "gitea", "github", or "gitlab". If empty, it will be auto-detected from BaseURL.
+// Provider can be "gitea", "github", "ghes", or "gitlab". If empty, it will be auto-detected from BaseURL.
 type ReleaseToFetch struct {
 	BaseURL  string
 	User     string
 	Repo     string
 	Latest   bool
-	Provider string // Optional: "gitea", "github", "gitlab" - auto-detected if empty
+	Provider string // Optional: "gitea", "github", "ghes", "gitlab" - auto-detected if empty
+	Token    string // Optional: API token, sent using the provider's auth header
+	Project  string // Optional: "owner/repo", a GitLab "group/subgroup/project" path or numeric ID; overrides User and Repo
+
+	// FallbackToTags makes a Latest request for a repository without releases
+	// return a release built from its highest stable semver tag (see GetTags).
+	FallbackToTags bool
+
+	// EnrichAssets issues a HEAD request per GitLab asset link to fill Size,
+	// ContentType and CreatedAt (Last-Modified), which GitLab's API omits.
+	EnrichAssets bool
+
+	// Filter keeps only the matching releases. When Filter or MaxResults is set,
+	// listing all releases follows pagination, newest first, and stops as soon
+	// as MaxResults releases matched instead of reading only the first page.
+	Filter     ReleaseFilter
+	MaxResults int
 }
 
+// OwnerKind selects whose repositories RepositoriesToFetch lists.
+type OwnerKind string
+
+const (
+	// OwnerUser lists the public repositories of User (the default)
+	OwnerUser OwnerKind = "user"
+	// OwnerOrg lists the repositories of the Gitea/GitHub organization or GitLab group named by User
+	OwnerOrg OwnerKind = "org"
+	// OwnerAuthenticated lists the repositories of the Token's owner, including private ones
+	OwnerAuthenticated OwnerKind = "authenticated"
+)
+
 // RepositoriesToFetch represents which repositories to list.
 // The legacy typo WithReleas is still honoured; prefer WithReleases.
-// Provider can be "gitea", "github", or "gitlab". If empty, it will be auto-detected from BaseURL.
+// Provider can be "gitea", "github", "ghes", or "gitlab". If empty, it will be auto-detected from BaseURL.
 type RepositoriesToFetch struct {
 	BaseURL      string
 	User         string
 	WithReleases bool
-	Provider     string // Optional: "gitea", "github", "gitlab" - auto-detected if empty
+	Provider     string    // Optional: "gitea", "github", "ghes", "gitlab" - auto-detected if empty
+	Token        string    // Optional: API token, sent using the provider's auth header
+	Owner        OwnerKind // Optional: OwnerUser (default), OwnerOrg or OwnerAuthenticated
+
+	// Group is shorthand for Owner: OwnerOrg with User set to the group; on GitLab
+	// it may be a nested path ("group/subgroup") or ID. IncludeSubgroups is GitLab only.
+	Group            string
+	IncludeSubgroups bool
+
+	// Filters applied to the listed repositories. Visibility is "public",
+	// "private" or "internal"; empty keeps all. Topic keeps repositories tagged with it.
+	Visibility      string
+	ExcludeArchived bool
+	ExcludeForks    bool
+	Topic           string
+
+	// CountReleases fetches an accurate ReleaseCounter for every GitHub and GitLab
+	// repository (Gitea reports it natively) with one extra request per repository,
+	// Concurrency at a time (default 8). WithReleases then filters on the real count.
+	CountReleases bool
+	Concurrency   int
+
+	// UseGraphQL lists GitHub repositories through the GraphQL API, which returns
+	// accurate release counts and latest releases in one paged query. Requires Token.
+	UseGraphQL bool
+}
+
+// RepositorySearch represents a repository search using the provider's search endpoint.
+// The embedded RepositoriesToFetch supplies BaseURL, Provider, Token, the owner
+// (User or Group) to search within and the client-side filters; its Topic is also
+// sent to the server. Sort is "name", "stars", "forks", "created" or "updated" and
+// Order "asc" or "desc"; MaxResults stops paging once that many results passed the filters.
+type RepositorySearch struct {
+	RepositoriesToFetch
+	Query      string
+	Language   string
+	Sort       string
+	Order      string
+	MaxResults int
+}
+
+// ReleaseSpec describes a release to create with CreateRelease or the new state
+// given to UpdateRelease. TargetCommitish (branch or SHA) is only used when the
+// tag does not exist yet; an empty TagName in an update keeps the current tag.
+type ReleaseSpec struct {
+	TagName         string
+	TargetCommitish string
+	Name            string
+	Body            string
+	Draft           bool
+	Prerelease      bool
+}
+
+// AssetUpload describes a file to attach to a release with UploadAsset.
+type AssetUpload struct {
+	Path        string                  // File to upload
+	Name        string                  // Asset name; defaults to the base name of Path
+	ContentType string                  // Detected from the extension, then the content, when empty
+	Progress    func(sent, total int64) // Optional: called as the file is sent
+	Retries     int                     // Attempts after a network error, 429 or 5xx; 0 means 3, negative disables
+}
+
+// MirrorOptions controls MirrorRelease and SyncReleases.
+// Tags and ExcludeTags are path.Match patterns such as "v1.*"; empty Tags mirrors every tag.
+type MirrorOptions struct {
+	Tags            []string
+	ExcludeTags     []string
+	IncludeDrafts   bool   // Mirror source drafts too; they are skipped by default
+	SkipAssets      bool   // Mirror release metadata only
+	TargetCommitish string // Branch or SHA used when the tag does not exist on the target yet
+	WorkDir         string // Scratch directory for downloaded assets; defaults to os.TempDir()
+	DryRun          bool   // Only plan; make no changes to the target
+}
+
+// MirrorActionKind is the kind of step in a MirrorPlan.
+type MirrorActionKind string
+
+const (
+	// MirrorCreateRelease creates the release on the target
+	MirrorCreateRelease MirrorActionKind = "create-release"
+	// MirrorUploadAsset uploads an asset the target release lacks
+	MirrorUploadAsset MirrorActionKind = "upload-asset"
+	// MirrorUpToDate marks a target release that already has every asset
+	MirrorUpToDate MirrorActionKind = "up-to-date"
+	// MirrorSkipped marks a release the target cannot represent, such as a draft on GitLab
+	MirrorSkipped MirrorActionKind = "skipped"
+)
+
+// MirrorAction is one step of a MirrorPlan. Asset is empty for release-level steps;
+// Reason says why a MirrorSkipped release was skipped.
+type MirrorAction struct {
+	Kind   MirrorActionKind
+	Tag    string
+	Asset  string
+	Reason string
+}
+
+// MirrorPlan lists the steps a mirror run takes, or would take with DryRun.
+type MirrorPlan struct {
+	Actions []MirrorAction
+}
+
+// WatchOptions controls a Watcher.
+type WatchOptions struct {
+	Interval    time.Duration               // Time between polls; defaults to 5 minutes
+	Jitter      time.Duration               // Up to this much random delay is added to every interval
+	StateFile   string                      // JSON file the seen releases and ETags persist in; empty keeps them in memory
+	EmitInitial bool                        // Report the releases found by the first poll of a target; by default they are only recorded
+	OnEvent     func(WatchEvent)            // Optional: receives events instead of the Events channel
+	OnError     func(ReleaseToFetch, error) // Optional: called when polling a target fails
+}
+
+// WatchEventKind is the kind of change a WatchEvent reports.
+type WatchEventKind string
+
+const (
+	WatchReleaseCreated WatchEventKind = "release-created"
+	WatchReleaseUpdated WatchEventKind = "release-updated"
+	WatchReleaseDeleted WatchEventKind = "release-deleted"
+	WatchAssetAdded     WatchEventKind = "asset-added"
+	WatchAssetUpdated   WatchEventKind = "asset-updated"
+	WatchAssetRemoved   WatchEventKind = "asset-removed"
+)
+
+// WatchEvent is a change a Watcher detected in one of its targets. Release is
+// the current release, or the last seen one for WatchReleaseDeleted; Asset is
+// set for asset events only.
+type WatchEvent struct {
+	Kind    WatchEventKind
+	Target  ReleaseToFetch
+	Release Release
+	Asset   Asset
+	Time    time.Time
+}
+
+// DownloadStats is a snapshot of the download counts of one or more repositories.
+type DownloadStats struct {
+	Time         time.Time             `json:"time"`
+	Repositories []RepositoryDownloads `json:"repositories"`
+}
+
+// RepositoryDownloads are the download counts of a repository's releases.
+// Totals only include assets whose download count the provider reports (see
+// CapabilityAssetDownloads); UnknownAssets counts the others.
+type RepositoryDownloads struct {
+	Repository    string              `json:"repository"`
+	Provider      string              `json:"provider,omitempty"`
+	Downloads     int                 `json:"downloads"`
+	UnknownAssets int                 `json:"unknown_assets,omitempty"`
+	Releases      []ReleaseDownloads  `json:"releases"`
+	Platforms     []PlatformDownloads `json:"platforms"`
+}
+
+// ReleaseDownloads are the download counts of a release and its assets.
+type ReleaseDownloads struct {
+	Tag           string           `json:"tag"`
+	PublishedAt   string           `json:"published_at,omitempty"`
+	Prerelease    bool             `json:"prerelease,omitempty"`
+	Downloads     int              `json:"downloads"`
+	UnknownAssets int              `json:"unknown_assets,omitempty"`
+	Assets        []AssetDownloads `json:"assets"`
+}
+
+// AssetDownloads is the download count of an asset. OS and Arch come from
+// AssetPlatform. Known is false when the provider does not report downloads.
+type AssetDownloads struct {
+	Name      string `json:"name"`
+	OS        string `json:"os,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	Downloads int    `json:"downloads"`
+	Known     bool   `json:"known"`
+}
+
+// PlatformDownloads are the downloads of every asset built for one platform.
+// Assets without a recognizable platform are counted with an empty OS and Arch.
+type PlatformDownloads struct {
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Downloads int    `json:"downloads"`
+}
+
+// DownloadTrend is the download count of an asset in one snapshot of a
+// StatsStore, and the downloads gained since the previous snapshot.
+type DownloadTrend struct {
+	Time       time.Time `json:"time"`
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag"`
+	Asset      string    `json:"asset"`
+	Downloads  int       `json:"downloads"`
+	Delta      int       `json:"delta"`
+}
+
+// DownloadTrends is the history of a StatsStore, oldest snapshot first.
+type DownloadTrends []DownloadTrend
+
+// WebhookOptions configures NewWebhookHandler.
+type WebhookOptions struct {
+	Secret    string             // Webhook secret: the HMAC key for Gitea and GitHub, the token for GitLab
+	Provider  string             // Optional: only accept webhooks from this provider ("gitea", "github", "ghes", "gitlab")
+	OnRelease func(WebhookEvent) // Called for every release event
+}
+
+// WebhookAction is the provider-neutral action of a release webhook.
+type WebhookAction string
+
+const (
+	WebhookCreated     WebhookAction = "created"     // A draft release was created (GitHub)
+	WebhookPublished   WebhookAction = "published"   // A release was published
+	WebhookUpdated     WebhookAction = "updated"     // A release was edited
+	WebhookDeleted     WebhookAction = "deleted"     // A release was deleted
+	WebhookUnpublished WebhookAction = "unpublished" // A release was turned back into a draft (GitHub)
+)
+
+// WebhookEvent is a release webhook decoded by ParseWebhook. Provider is
+// "gitea", "github" or "gitlab"; RawAction is the action as the provider sent it.
+// Action is empty for actions that repeat another event, such as GitHub's "released".
+type WebhookEvent struct {
+	Provider   string
+	Action     WebhookAction
+	RawAction  string
+	Release    Release
+	Repository Repository
+}
+
+// Tag represents a git tag. Date is the commit date and is empty on GitHub,
+// whose tags endpoint does not report it.
+type Tag struct {
+	Name       string `json:"name"`
+	CommitSHA  string `json:"commit_sha"`
+	Date       string `json:"date"`
+	Message    string `json:"message"`
+	TarballURL string `json:"tarball_url"`
+	ZipballURL string `json:"zipball_url"`
+}
+
+// RepositoryRelease pairs a repository with its latest release.
+// Latest is nil when the repository has no releases.
+type RepositoryRelease struct {
+	Repository Repository
+	Latest     *Release
 }
 
 // Release represents a release payload from Gitea.
@@ -66,6 +344,22 @@ type Release struct {
 	PublishedAt string `json:"published_at"`
 	Author      Author
 	Assets      []Asset
+
+	// CreatedTime and PublishedTime are CreatedAt and PublishedAt parsed with
+	// ParseTimestamp; they are zero when the provider did not report the time.
+	CreatedTime   time.Time `json:"-"`
+	PublishedTime time.Time `json:"-"`
+
+	// TargetCommitish is the branch or SHA the release was created from (Gitea, GitHub).
+	// CommitSHA is the tagged commit when the provider reports it; see ResolveReleaseCommit.
+	TargetCommitish string `json:"target_commitish"`
+	CommitSHA       string `json:"commit_sha"`
+
+	// UploadURL is where GitHub accepts the release's assets (an RFC 6570 template)
+	UploadURL string `json:"upload_url,omitempty"`
+
+	// Capabilities tells which fields the provider reported; see Asset.Known for assets
+	Capabilities Capabilities `json:"-"`
 }
 
 // Author represents the author of a release
@@ -81,12 +375,19 @@ type Author struct {
 type Asset struct {
 	ID                 int    `json:"id"`
 	Name               string `json:"name"`
+	URL                string `json:"url"` // API URL, used for authenticated downloads
 	Size               int64  `json:"size"`
 	DownloadCount      int    `json:"download_count"`
 	CreatedAt          string `json:"created_at"`
 	UUID               string `json:"uuid"`
 	BrowserDownloadURL string `json:"browser_download_url"`
-	Type               string `json:"type"` // Detect the asset type
+	Type               string `json:"type"`                   // Detect the asset type; GitLab's link type
+	ContentType        string `json:"content_type,omitempty"` // MIME type, when reported or enriched
+
+	CreatedTime time.Time `json:"-"` // CreatedAt parsed with ParseTimestamp
+
+	// Known holds the asset capabilities, such as CapabilityAssetSize, of the fields that were reported
+	Known Capabilities `json:"-"`
 }
 
 // Repository represents a repository from a user or organization.
@@ -158,6 +459,7 @@ type Repository struct {
 	HasPullRequests               bool      `json:"has_pull_requests"`
 	HasProjects                   bool      `json:"has_projects"`
 	HasReleases                   bool      `json:"has_releases"`
+	Topics                        []string  `json:"topics"`
 	HasPackages                   bool      `json:"has_packages"`
 	HasActions                    bool      `json:"has_actions"`
 	IgnoreWhitespaceConflicts     bool      `json:"ignore_whitespace_conflicts"`
@@ -174,4 +476,7 @@ type Repository struct {
 	MirrorInterval                string    `json:"mirror_interval"`
 	MirrorUpdated                 time.Time `json:"mirror_updated"`
 	// RepoTransfer              interface{} `json:"repo_transfer"`
+
+	// Capabilities tells which fields the provider reported, such as CapabilityReleaseCount
+	Capabilities Capabilities `json:"-"`
 }
@@ -0,0 +1,107 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// GetTags returns every tag of the repository selected by r, following pagination.
+// r.Latest is ignored.
+func GetTags(r ReleaseToFetch) ([]Tag, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	lister, ok := provider.(providers.TagLister)
+	if !ok {
+		return nil, fmt.Errorf("provider cannot list tags: %w", errors.ErrUnsupported)
+	}
+
+	user, repo := repoCoordinates(r, providerType)
+	headers := requestHeaders(provider, r.Token)
+	pages, err := fetchAllPages(withPageSize(lister.GetTagsURL(baseURL, user, repo), providerType), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	for _, apiData := range pages {
+		providerTags, err := lister.NormalizeTags(apiData)
+		if err != nil {
+			return nil, err
+		}
+		for _, pt := range providerTags {
+			tag := Tag(pt)
+			if gl, ok := provider.(*providers.GitLabProvider); ok {
+				tag.TarballURL = gl.ArchiveURL(baseURL, user, repo, pt.Name, "tar.gz")
+				tag.ZipballURL = gl.ArchiveURL(baseURL, user, repo, pt.Name, "zip")
+			}
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// HighestSemverTag returns the highest stable version among tags, ignoring tags
+// that are not versions (such as "nightly") and prereleases (such as "v2.0.0-rc1").
+// The second result is false when no tag qualifies.
+func HighestSemverTag(tags []Tag) (Tag, bool) {
+	var best Tag
+	found := false
+	for _, tag := range tags {
+		if !isStableVersion(tag.Name) {
+			continue
+		}
+		if !found || CompareVersions(VersionStrings{Own: best.Name, Latest: tag.Name}) < 0 {
+			best, found = tag, true
+		}
+	}
+	return best, found
+}
+
+// isStableVersion reports whether v looks like a release version: digits and
+// dots after the usual prefixes, optionally followed by a commit hash.
+func isStableVersion(v string) bool {
+	core := versionCore(v)
+	if core == "" || core[0] < '0' || core[0] > '9' {
+		return false
+	}
+	return strings.Trim(core, "0123456789.") == ""
+}
+
+// latestFromTags returns the release built from the highest stable semver tag,
+// or no releases when the repository has no such tag.
+func latestFromTags(r ReleaseToFetch) ([]Release, error) {
+	tags, err := GetTags(r)
+	if err != nil {
+		return nil, err
+	}
+	tag, ok := HighestSemverTag(tags)
+	if !ok {
+		return []Release{}, nil
+	}
+	return []Release{releaseFromTag(tag)}, nil
+}
+
+// releaseFromTag builds the release reported for a tag-only repository.
+func releaseFromTag(tag Tag) Release {
+	date, _ := ParseTimestamp(tag.Date)
+	return Release{
+		TagName:     tag.Name,
+		Name:        tag.Name,
+		CommitSHA:   tag.CommitSHA,
+		Body:        tag.Message,
+		TarballURL:  tag.TarballURL,
+		ZipballURL:  tag.ZipballURL,
+		CreatedAt:   tag.Date,
+		PublishedAt: tag.Date,
+
+		CreatedTime:   date,
+		PublishedTime: date,
+
+		Capabilities: providers.CapReleaseCommitSHA,
+	}
+}
@@ -0,0 +1,87 @@
+package gitearelease
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// ParseTimestamp parses a timestamp as reported by Gitea, GitHub, GitLab or git:
+// RFC 3339 with or without fractional seconds and offsets, GitLab's webhook
+// "2006-01-02 15:04:05 UTC" and HTTP dates. An empty string is the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	return providers.ParseTimestamp(s)
+}
+
+// formatTimestamp returns t, or s parsed when t is zero, as RFC 3339 in UTC.
+// It returns "" when neither holds a valid time.
+func formatTimestamp(t time.Time, s string) string {
+	if t.IsZero() {
+		t, _ = ParseTimestamp(s)
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// UnmarshalJSON decodes a release and parses its timestamps, so releases read
+// back from JSON (such as a Watcher state file) have CreatedTime and PublishedTime.
+func (r *Release) UnmarshalJSON(data []byte) error {
+	type release Release
+	if err := json.Unmarshal(data, (*release)(r)); err != nil {
+		return err
+	}
+	r.CreatedTime, _ = ParseTimestamp(r.CreatedAt)
+	r.PublishedTime, _ = ParseTimestamp(r.PublishedAt)
+	return nil
+}
+
+// UnmarshalJSON decodes an asset and parses its CreatedAt into CreatedTime.
+func (a *Asset) UnmarshalJSON(data []byte) error {
+	type asset Asset
+	if err := json.Unmarshal(data, (*asset)(a)); err != nil {
+		return err
+	}
+	a.CreatedTime, _ = ParseTimestamp(a.CreatedAt)
+	return nil
+}
+
+// ReleaseTime returns when the release was published, or created if it was
+// never published (drafts). It is zero when neither is known. Releases built
+// by hand with only the string fields set are parsed on the fly.
+func (r Release) ReleaseTime() time.Time {
+	for _, t := range []time.Time{r.PublishedTime, r.CreatedTime} {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	for _, s := range []string{r.PublishedAt, r.CreatedAt} {
+		if t, err := ParseTimestamp(s); err == nil && !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ReleasedAfter returns the releases whose ReleaseTime is after t. Releases
+// without a known time are left out.
+func ReleasedAfter(releases []Release, t time.Time) []Release {
+	return FilterReleases(releases, PublishedAfter(t))
+}
+
+// ReleasedBefore returns the releases whose ReleaseTime is before t. Releases
+// without a known time are left out.
+func ReleasedBefore(releases []Release, t time.Time) []Release {
+	return FilterReleases(releases, func(rel Release) bool {
+		rt := rel.ReleaseTime()
+		return !rt.IsZero() && rt.Before(t)
+	})
+}
+
+// SortByReleaseTime sorts releases newest first. Releases without a known time
+// go last, keeping their order.
+func SortByReleaseTime(releases []Release) {
+	SortReleases(releases, Descending(ByPublished))
+}
@@ -0,0 +1,89 @@
+package gitearelease
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []string{
+		"2024-05-01T10:00:00Z",
+		"2024-05-01T10:00:00.000Z",
+		"2024-05-01T12:00:00+02:00",
+		"2024-05-01T12:00:00.539+02:00",
+		"2024-05-01T12:00:00+0200",
+		"2024-05-01T10:00:00",
+		"2024-05-01 10:00:00 UTC",
+		"2024-05-01 12:00:00 +0200",
+		"Wed, 01 May 2024 10:00:00 GMT",
+	}
+	for _, s := range tests {
+		got, err := ParseTimestamp(s)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", s, err)
+			continue
+		}
+		if !got.Truncate(time.Second).Equal(want) {
+			t.Errorf("Expected %s for %q, got %s", want, s, got)
+		}
+	}
+
+	if got, err := ParseTimestamp(""); err != nil || !got.IsZero() {
+		t.Errorf("Expected the zero time for an empty string, got %s, %v", got, err)
+	}
+	if _, err := ParseTimestamp("yesterday"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestReleasedAfter(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.0.0", PublishedAt: "2024-01-01T00:00:00Z"},
+		{TagName: "v1.2.0", PublishedAt: "2024-06-01T00:00:00.123Z"},
+		{TagName: "v1.1.0", PublishedAt: "2024-03-01T00:00:00+01:00"},
+		{TagName: "draft", CreatedAt: "2024-07-01 00:00:00 UTC"},
+		{TagName: "unknown"},
+	}
+	cutoff := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	after := ReleasedAfter(releases, cutoff)
+	if len(after) != 3 || after[0].TagName != "v1.2.0" || after[2].TagName != "draft" {
+		t.Errorf("Expected v1.2.0, v1.1.0 and draft, got %+v", after)
+	}
+	before := ReleasedBefore(releases, cutoff)
+	if len(before) != 1 || before[0].TagName != "v1.0.0" {
+		t.Errorf("Expected v1.0.0, got %+v", before)
+	}
+
+	SortByReleaseTime(releases)
+	var order []string
+	for _, rel := range releases {
+		order = append(order, rel.TagName)
+	}
+	if got := fmt.Sprint(order); got != "[draft v1.2.0 v1.1.0 v1.0.0 unknown]" {
+		t.Errorf("Expected newest first with unknown last, got %s", got)
+	}
+}
+
+func TestRelease_UnmarshalJSONParsesTimes(t *testing.T) {
+	var rel Release
+	data := `{"tag_name": "v1.0.0", "created_at": "2024-05-01T10:00:00+02:00", "published_at": "2024-05-01T12:00:00.5Z", "Assets": [{"name": "a.zip", "created_at": "2024-05-01T09:00:00Z"}]}`
+	if err := json.Unmarshal([]byte(data), &rel); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !rel.CreatedTime.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected CreatedTime 08:00 UTC, got %s", rel.CreatedTime)
+	}
+	if rel.PublishedTime.Nanosecond() != 500000000 {
+		t.Errorf("Expected fractional seconds to be kept, got %s", rel.PublishedTime)
+	}
+	if rel.Assets[0].CreatedTime.IsZero() {
+		t.Error("Expected the asset CreatedTime to be parsed")
+	}
+	if rel.PublishedAt != "2024-05-01T12:00:00.5Z" {
+		t.Errorf("Expected the string field to be kept, got %s", rel.PublishedAt)
+	}
+}
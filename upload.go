@@ -0,0 +1,242 @@
+package gitearelease
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// defaultUploadRetries is used when AssetUpload.Retries is zero.
+const defaultUploadRetries = 3
+
+// uploadRetryDelay is multiplied by the attempt number between upload retries.
+var uploadRetryDelay = 2 * time.Second
+
+// UploadAsset streams upload.Path to release and returns the new asset with its
+// BrowserDownloadURL. Gitea and GitHub attach the file directly; on GitLab it is
+// stored in the project's generic package registry and linked to the release.
+// Large files may need a longer SetHTTPTimeout.
+func UploadAsset(r ReleaseToFetch, release Release, upload AssetUpload) (Asset, error) {
+	w, aw, err := newAssetWriter(r)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	name := upload.Name
+	if name == "" {
+		name = filepath.Base(upload.Path)
+	}
+	contentType := upload.ContentType
+	if contentType == "" {
+		if contentType, err = detectContentType(upload.Path); err != nil {
+			return Asset{}, err
+		}
+	}
+
+	ref := providerReleaseRef(release)
+	req := aw.UploadAssetRequest(w.baseURL, w.user, w.repo, ref, name)
+	data, err := uploadWithRetries(req, w.headers, upload, name, contentType)
+	if err != nil {
+		return Asset{}, err
+	}
+	if linkURL, payload := aw.LinkAssetRequest(w.baseURL, w.user, w.repo, ref, name, req.URL); linkURL != "" {
+		if data, err = sendJSON(http.MethodPost, linkURL, w.headers, payload); err != nil {
+			return Asset{}, err
+		}
+	}
+
+	pa, err := aw.NormalizeAsset(data)
+	if err != nil {
+		return Asset{}, err
+	}
+	asset := convertProviderAsset(pa)
+	if asset.Size == 0 {
+		if info, err := os.Stat(upload.Path); err == nil {
+			asset.Size = info.Size()
+		}
+	}
+	return asset, nil
+}
+
+// DeleteAsset removes asset from release. On GitLab only the release link is
+// deleted; the package file stays in the registry.
+func DeleteAsset(r ReleaseToFetch, release Release, asset Asset) error {
+	w, aw, err := newAssetWriter(r)
+	if err != nil {
+		return err
+	}
+	return w.delete(aw.DeleteAssetURL(w.baseURL, w.user, w.repo, providerReleaseRef(release), providerAssetRef(asset)))
+}
+
+func newAssetWriter(r ReleaseToFetch) (*releaseWriter, providers.AssetWriter, error) {
+	w, err := newReleaseWriter(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	aw, ok := w.writer.(providers.AssetWriter)
+	if !ok {
+		return nil, nil, fmt.Errorf("provider cannot upload assets: %w", errors.ErrUnsupported)
+	}
+	return w, aw, nil
+}
+
+// providerAssetRef returns the identifying fields of asset that providers need to address it.
+func providerAssetRef(asset Asset) providers.Asset {
+	return providers.Asset{ID: asset.ID, Name: asset.Name}
+}
+
+// detectContentType guesses the MIME type of path from its extension, then its first 512 bytes.
+func detectContentType(path string) (string, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("read %q: %w", path, err)
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// uploadWithRetries sends the file, retrying the failures isRetryable accepts.
+func uploadWithRetries(req providers.AssetUploadRequest, headers http.Header, upload AssetUpload, name, contentType string) ([]byte, error) {
+	retries := upload.Retries
+	if retries == 0 {
+		retries = defaultUploadRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		data, err := uploadFile(req, headers, upload, name, contentType)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= retries || !isRetryable(err, req.Method) {
+			return nil, err
+		}
+		time.Sleep(uploadRetryDelay * time.Duration(attempt+1))
+	}
+}
+
+// isRetryable reports whether a failed upload may succeed when sent again:
+// the server answered 429 or 5xx, or the request failed on the network.
+// A POST creates the asset, and a 5xx or a failure after connecting may
+// already have created it, so a POST is only retried after a 429 or when the
+// connection could not be made. Local errors, other statuses and failures
+// reading an accepted upload's response are final.
+func isRetryable(err error, method string) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500 && method != http.MethodPost
+	}
+	var ue *url.Error
+	if !errors.As(err, &ue) {
+		return false
+	}
+	if method == http.MethodPost {
+		var oe *net.OpError
+		return errors.As(ue.Err, &oe) && oe.Op == "dial"
+	}
+	var ne net.Error
+	return errors.As(ue.Err, &ne) || errors.Is(ue.Err, io.EOF) || errors.Is(ue.Err, io.ErrUnexpectedEOF)
+}
+
+// uploadFile streams the file once. Multipart bodies are framed around the file
+// so the Content-Length is known without buffering it.
+func uploadFile(req providers.AssetUploadRequest, headers http.Header, upload AssetUpload, name, contentType string) ([]byte, error) {
+	f, err := os.Open(upload.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader = &progressReader{r: f, total: info.Size(), fn: upload.Progress}
+	length := info.Size()
+	if req.FormField != "" {
+		var frame bytes.Buffer
+		mw := multipart.NewWriter(&frame)
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, req.FormField, name))
+		h.Set("Content-Type", contentType)
+		if _, err := mw.CreatePart(h); err != nil {
+			return nil, err
+		}
+		head := frame.Len()
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		prefix, suffix := frame.Bytes()[:head], frame.Bytes()[head:]
+
+		body = io.MultiReader(bytes.NewReader(prefix), body, bytes.NewReader(suffix))
+		length += int64(len(prefix) + len(suffix))
+		contentType = mw.FormDataContentType()
+	}
+
+	httpReq, err := http.NewRequest(req.Method, req.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("build %s %q: %w", req.Method, req.URL, err)
+	}
+	httpReq.ContentLength = length
+	for name, values := range headers {
+		for _, v := range values {
+			httpReq.Header.Add(name, v)
+		}
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", req.Method, req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &statusError{method: req.Method, url: req.URL, status: resp.Status, code: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return data, nil
+}
+
+// progressReader reports the bytes read so far to fn.
+type progressReader struct {
+	r     io.Reader
+	sent  int64
+	total int64
+	fn    func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if n > 0 && p.fn != nil {
+		p.fn(p.sent, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,77 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// ErrNoBuildRevision is returned by VerifyBuild when the running binary carries no
+// vcs.revision, e.g. when it was built outside a git checkout or with -buildvcs=false.
+var ErrNoBuildRevision = errors.New("binary has no embedded VCS revision")
+
+// ResolveReleaseCommit returns the full SHA of the commit rel's tag points at.
+// GitLab releases and GitHub GraphQL results carry it; otherwise the tag is
+// resolved through the provider, peeling annotated tags. TargetCommitish is never
+// used: it names what the tag was created from, which may have moved since.
+func ResolveReleaseCommit(r ReleaseToFetch, rel Release) (string, error) {
+	if rel.CommitSHA != "" {
+		return rel.CommitSHA, nil
+	}
+
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	lister, ok := provider.(providers.TagLister)
+	if !ok {
+		return "", fmt.Errorf("provider cannot resolve tags: %w", errors.ErrUnsupported)
+	}
+
+	user, repo := repoCoordinates(r, providerType)
+	apiURL := lister.GetTagCommitURL(baseURL, user, repo, rel.TagName)
+	apiData, err := fetchDataWithHeaders(apiURL, requestHeaders(provider, r.Token))
+	if err != nil {
+		return "", err
+	}
+
+	sha, err := lister.NormalizeTagCommit(apiData)
+	if err != nil {
+		return "", err
+	}
+	if sha == "" {
+		return "", fmt.Errorf("GET %q: no commit for tag %q", apiURL, rel.TagName)
+	}
+	return strings.ToLower(sha), nil
+}
+
+// VerifyBuild reports whether the running binary was built from the commit rel's
+// tag points at, comparing it with the vcs.revision the Go toolchain embeds.
+// A binary built from a modified working tree is never verified.
+func VerifyBuild(r ReleaseToFetch, rel Release) (bool, error) {
+	revision, modified := buildRevision()
+	if revision == "" {
+		return false, ErrNoBuildRevision
+	}
+
+	sha, err := ResolveReleaseCommit(r, rel)
+	if err != nil {
+		return false, err
+	}
+	return !modified && sameCommit(revision, sha), nil
+}
+
+// sameCommit reports whether a and b name the same commit. Either may be
+// abbreviated, but at least to the 7 characters git uses by default.
+func sameCommit(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if len(a) < 7 || len(b) < 7 {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return strings.HasPrefix(b, a)
+}
@@ -0,0 +1,364 @@
+package gitearelease
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// defaultWatchInterval is the poll interval used when WatchOptions.Interval is not set.
+const defaultWatchInterval = 5 * time.Minute
+
+// watchStateVersion is the format version of the watcher state file.
+const watchStateVersion = 1
+
+// Watcher polls a set of repositories for release changes: new, updated and
+// deleted releases, and added, updated and removed assets. Listings are
+// fetched with conditional requests, so unchanged repositories cost a 304
+// (which GitHub does not count against the rate limit).
+type Watcher struct {
+	targets []ReleaseToFetch
+	opts    WatchOptions
+	events  chan WatchEvent
+
+	mu    sync.Mutex
+	state watchState
+}
+
+// watchState is what a Watcher remembers between polls and, with a
+// StateFile, between runs.
+type watchState struct {
+	Version int                     `json:"version"`
+	Targets map[string]*watchTarget `json:"targets"`
+}
+
+// watchTarget is the last seen state of one repository.
+type watchTarget struct {
+	Releases []Release            `json:"releases"`
+	Pages    map[string]watchPage `json:"pages"`
+}
+
+// watchPage is a cached listing page with the ETag it was served with.
+type watchPage struct {
+	ETag string          `json:"etag"`
+	Next string          `json:"next,omitempty"`
+	Body json.RawMessage `json:"body"`
+}
+
+// NewWatcher returns a Watcher for targets, loading opts.StateFile if it exists.
+func NewWatcher(targets []ReleaseToFetch, opts WatchOptions) (*Watcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	w := &Watcher{
+		targets: targets,
+		opts:    opts,
+		events:  make(chan WatchEvent, 64),
+		state:   watchState{Version: watchStateVersion, Targets: map[string]*watchTarget{}},
+	}
+	if opts.StateFile == "" {
+		return w, nil
+	}
+
+	data, err := os.ReadFile(opts.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read watch state: %w", err)
+	}
+	var state watchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse watch state %q: %w", opts.StateFile, err)
+	}
+	if state.Version != watchStateVersion {
+		return nil, fmt.Errorf("watch state %q: unsupported version %d", opts.StateFile, state.Version)
+	}
+	if state.Targets != nil {
+		w.state.Targets = state.Targets
+	}
+	return w, nil
+}
+
+// Events returns the channel events are delivered on when WatchOptions.OnEvent
+// is nil. It is closed when Run returns.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Run polls every target immediately and then once per interval (plus jitter)
+// until ctx is done. Errors polling a target go to WatchOptions.OnError and do
+// not stop the watcher; Run only fails if the state file cannot be written.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			return err
+		}
+
+		delay := w.opts.Interval
+		if w.opts.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(w.opts.Jitter)))
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll checks every target once and returns the events found, without
+// delivering them to OnEvent or the Events channel. Errors from individual
+// targets are joined.
+func (w *Watcher) Poll() ([]WatchEvent, error) {
+	var events []WatchEvent
+	var errs []error
+	for _, target := range w.targets {
+		found, err := w.check(target)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		events = append(events, found...)
+	}
+	if err := w.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return events, errors.Join(errs...)
+}
+
+// poll checks every target and delivers its events, saving the state after
+// each target so a restart does not report them again.
+func (w *Watcher) poll(ctx context.Context) error {
+	for _, target := range w.targets {
+		if ctx.Err() != nil {
+			return nil
+		}
+		events, err := w.check(target)
+		if err != nil {
+			if w.opts.OnError != nil {
+				w.opts.OnError(target, err)
+			}
+			continue
+		}
+		for _, ev := range events {
+			if w.opts.OnEvent != nil {
+				w.opts.OnEvent(ev)
+				continue
+			}
+			select {
+			case w.events <- ev:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if len(events) > 0 {
+			if err := w.save(); err != nil {
+				return err
+			}
+		}
+	}
+	return w.save()
+}
+
+// check fetches target's releases and diffs them against the last seen state.
+func (w *Watcher) check(target ReleaseToFetch) ([]WatchEvent, error) {
+	providerType := resolveProviderType(target.Provider, target.BaseURL)
+	baseURL := normalizeBaseURL(target.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+	user, repo := repoCoordinates(target, providerType)
+	key := fmt.Sprintf("%s %s/%s/%s", providerType, baseURL, user, repo)
+
+	w.mu.Lock()
+	previous, seen := w.state.Targets[key]
+	w.mu.Unlock()
+
+	cache := map[string]watchPage{}
+	if seen {
+		cache = previous.Pages
+	}
+	apiURL := withPageSize(provider.GetReleasesURL(baseURL, user, repo, false), providerType)
+	pages, fresh, err := fetchPagesCached(apiURL, requestHeaders(provider, target.Token), cache)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := normalizeReleasePages(provider, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []WatchEvent
+	if seen || w.opts.EmitInitial {
+		var old []Release
+		if seen {
+			old = previous.Releases
+		}
+		events = diffReleases(target, old, releases, time.Now())
+	}
+
+	w.mu.Lock()
+	w.state.Targets[key] = &watchTarget{Releases: releases, Pages: fresh}
+	w.mu.Unlock()
+	return events, nil
+}
+
+// fetchPagesCached follows a paginated listing like fetchAllPages, sending the
+// cached ETag of every page and reusing its cached body on a 304. It returns
+// the pages and the cache entries for them.
+func fetchPagesCached(url string, headers http.Header, cache map[string]watchPage) ([][]byte, map[string]watchPage, error) {
+	var pages [][]byte
+	fresh := map[string]watchPage{}
+	for url != "" && len(pages) < maxPages {
+		h := headers.Clone()
+		cached, ok := cache[url]
+		if ok && cached.ETag != "" {
+			h.Set("If-None-Match", cached.ETag)
+		}
+
+		resp, err := doRequest(http.MethodGet, url, h)
+		if err != nil {
+			return nil, nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && ok:
+			fresh[url] = cached
+			pages = append(pages, cached.Body)
+			url = cached.Next
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return nil, nil, &statusError{method: http.MethodGet, url: url, status: resp.Status, code: resp.StatusCode}
+		case err != nil:
+			return nil, nil, fmt.Errorf("read body: %w", err)
+		}
+
+		next := linkTarget(resp.Header.Get("Link"), "next")
+		if etag := resp.Header.Get("ETag"); etag != "" && json.Valid(body) {
+			fresh[url] = watchPage{ETag: etag, Next: next, Body: body}
+		}
+		pages = append(pages, body)
+		url = next
+	}
+	return pages, fresh, nil
+}
+
+// diffReleases reports the changes from old to current, matching releases by
+// tag and assets by name. Download counts are not changes.
+func diffReleases(target ReleaseToFetch, old, current []Release, now time.Time) []WatchEvent {
+	target.Token = "" // events are handed to arbitrary code; do not pass the credentials along
+
+	oldByTag := make(map[string]Release, len(old))
+	for _, rel := range old {
+		oldByTag[rel.TagName] = rel
+	}
+
+	var events []WatchEvent
+	emit := func(kind WatchEventKind, rel Release, asset Asset) {
+		events = append(events, WatchEvent{Kind: kind, Target: target, Release: rel, Asset: asset, Time: now})
+	}
+
+	currentTags := make(map[string]bool, len(current))
+	for _, rel := range current {
+		currentTags[rel.TagName] = true
+		prev, ok := oldByTag[rel.TagName]
+		if !ok {
+			emit(WatchReleaseCreated, rel, Asset{})
+			continue
+		}
+		if releaseChanged(prev, rel) {
+			emit(WatchReleaseUpdated, rel, Asset{})
+		}
+
+		prevAssets := make(map[string]Asset, len(prev.Assets))
+		for _, a := range prev.Assets {
+			prevAssets[a.Name] = a
+		}
+		for _, a := range rel.Assets {
+			pa, ok := prevAssets[a.Name]
+			delete(prevAssets, a.Name)
+			switch {
+			case !ok:
+				emit(WatchAssetAdded, rel, a)
+			case pa.ID != a.ID || pa.Size != a.Size || pa.BrowserDownloadURL != a.BrowserDownloadURL:
+				emit(WatchAssetUpdated, rel, a)
+			}
+		}
+		for _, a := range prev.Assets {
+			if _, removed := prevAssets[a.Name]; removed {
+				emit(WatchAssetRemoved, rel, a)
+			}
+		}
+	}
+
+	for _, rel := range old {
+		if !currentTags[rel.TagName] {
+			emit(WatchReleaseDeleted, rel, Asset{})
+		}
+	}
+	return events
+}
+
+// releaseChanged reports whether the release-level fields of a release changed.
+func releaseChanged(a, b Release) bool {
+	return a.ID != b.ID || a.Name != b.Name || a.Body != b.Body ||
+		a.Draft != b.Draft || a.Prerelease != b.Prerelease ||
+		a.PublishedAt != b.PublishedAt || a.TargetCommitish != b.TargetCommitish
+}
+
+// save writes the state file, if any, atomically.
+func (w *Watcher) save() error {
+	if w.opts.StateFile == "" {
+		return nil
+	}
+
+	w.mu.Lock()
+	data, err := json.Marshal(w.state)
+	w.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode watch state: %w", err)
+	}
+
+	if err := writeFileAtomic(w.opts.StateFile, data); err != nil {
+		return fmt.Errorf("write watch state: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the same
+// directory, so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
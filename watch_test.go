@@ -0,0 +1,136 @@
+package gitearelease
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	var mu sync.Mutex
+	body := `[{"id": 1, "tag_name": "v1.0.0", "name": "One", "assets": []}]`
+	etag := `"a"`
+	conditional := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/api/v1/repos/testuser/testrepo/releases" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer mockServer.Close()
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	target := ReleaseToFetch{BaseURL: mockServer.URL, User: "testuser", Repo: "testrepo", Provider: "gitea", Token: "secret"}
+	watcher, err := NewWatcher([]ReleaseToFetch{target}, WatchOptions{StateFile: stateFile})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	events, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected the first poll to only record releases, got %v", events)
+	}
+
+	events, _ = watcher.Poll()
+	if len(events) != 0 || conditional != 1 {
+		t.Errorf("Expected an unchanged 304 poll, got %d events and %d conditional requests", len(events), conditional)
+	}
+
+	mu.Lock()
+	body = `[
+		{"id": 2, "tag_name": "v1.1.0", "name": "Two", "assets": []},
+		{"id": 1, "tag_name": "v1.0.0", "name": "One (fixed)", "assets": [{"id": 7, "name": "app.tar.gz", "size": 10}]}
+	]`
+	etag = `"b"`
+	mu.Unlock()
+
+	events, err = watcher.Poll()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	kinds := map[WatchEventKind]string{}
+	for _, ev := range events {
+		kinds[ev.Kind] = ev.Release.TagName
+		if ev.Target.Token != "" {
+			t.Errorf("Expected events without the token, got %q", ev.Target.Token)
+		}
+	}
+	expected := map[WatchEventKind]string{
+		WatchReleaseCreated: "v1.1.0",
+		WatchReleaseUpdated: "v1.0.0",
+		WatchAssetAdded:     "v1.0.0",
+	}
+	if len(events) != len(expected) {
+		t.Errorf("Expected %d events, got %v", len(expected), events)
+	}
+	for kind, tag := range expected {
+		if kinds[kind] != tag {
+			t.Errorf("Expected %s for %s, got %v", kind, tag, kinds)
+		}
+	}
+
+	// A restarted watcher picks up the persisted state and ETags
+	restarted, err := NewWatcher([]ReleaseToFetch{target}, WatchOptions{StateFile: stateFile})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	events, _ = restarted.Poll()
+	if len(events) != 0 || conditional != 2 {
+		t.Errorf("Expected no events after a restart, got %d events and %d conditional requests", len(events), conditional)
+	}
+
+	mu.Lock()
+	body = `[{"id": 2, "tag_name": "v1.1.0", "name": "Two", "assets": []}]`
+	etag = `"c"`
+	mu.Unlock()
+
+	events, _ = restarted.Poll()
+	if len(events) != 1 || events[0].Kind != WatchReleaseDeleted || events[0].Release.TagName != "v1.0.0" {
+		t.Errorf("Expected v1.0.0 to be reported deleted, got %v", events)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "tag_name": "v1.0.0", "assets": [{"id": 3, "name": "app.zip"}]}]`))
+	}))
+	defer mockServer.Close()
+
+	target := ReleaseToFetch{BaseURL: mockServer.URL, User: "testuser", Repo: "testrepo", Provider: "gitea"}
+	watcher, err := NewWatcher([]ReleaseToFetch{target}, WatchOptions{Interval: time.Hour, EmitInitial: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	ev := <-watcher.Events()
+	if ev.Kind != WatchReleaseCreated || ev.Release.TagName != "v1.0.0" {
+		t.Errorf("Expected a release-created event for v1.0.0, got %+v", ev)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, open := <-watcher.Events(); open {
+		t.Error("Expected the events channel to be closed")
+	}
+}
@@ -0,0 +1,116 @@
+package gitearelease
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// ErrInvalidSignature is returned by ParseWebhook when the signature or token
+// of a webhook does not match the secret.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrUnsupportedEvent is returned, wrapped, by ParseWebhook for requests that are
+// not release webhooks, such as GitHub's ping or push events.
+var ErrUnsupportedEvent = errors.New("unsupported webhook event")
+
+// maxWebhookBody is the largest payload accepted; GitHub caps webhooks at 25 MB.
+const maxWebhookBody = 25 << 20
+
+// webhookProviders lists the providers in detection order. Gitea comes first
+// because it also sends GitHub's X-GitHub-Event header.
+var webhookProviders = []struct {
+	typ    providers.ProviderType
+	parser providers.WebhookParser
+}{
+	{providers.ProviderGitea, providers.NewGiteaProvider()},
+	{providers.ProviderGitLab, providers.NewGitLabProvider()},
+	{providers.ProviderGitHub, providers.NewGitHubProvider()},
+}
+
+// ParseWebhook validates a Gitea, GitHub or GitLab release webhook against
+// secret and decodes it. The provider is detected from the event header.
+// Requests with a missing or wrong signature (or GitLab token) fail with
+// ErrInvalidSignature, even when secret is empty.
+func ParseWebhook(r *http.Request, secret string) (WebhookEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		return WebhookEvent{}, fmt.Errorf("read body: %w", err)
+	}
+	if len(body) > maxWebhookBody {
+		return WebhookEvent{}, fmt.Errorf("read body: webhook payload exceeds %d bytes", maxWebhookBody)
+	}
+
+	for _, p := range webhookProviders {
+		event := p.parser.WebhookEvent(r.Header)
+		if event == "" {
+			continue
+		}
+		if !p.parser.VerifyWebhook(r.Header, body, secret) {
+			return WebhookEvent{}, ErrInvalidSignature
+		}
+
+		hook, ok, err := p.parser.NormalizeReleaseWebhook(event, body)
+		if err != nil {
+			return WebhookEvent{}, err
+		}
+		if !ok {
+			return WebhookEvent{}, fmt.Errorf("%s %q: %w", p.typ, event, ErrUnsupportedEvent)
+		}
+		return WebhookEvent{
+			Provider:   string(p.typ),
+			Action:     WebhookAction(hook.Action),
+			RawAction:  hook.RawAction,
+			Release:    convertProviderRelease(hook.Release),
+			Repository: convertProviderRepository(hook.Repository),
+		}, nil
+	}
+	return WebhookEvent{}, fmt.Errorf("no Gitea, GitHub or GitLab event header: %w", ErrUnsupportedEvent)
+}
+
+// NewWebhookHandler returns an http.Handler that receives release webhooks,
+// validates them with ParseWebhook and passes each release event to
+// opts.OnRelease. It answers 204 for handled and ignored events (such as
+// pings), 401 for bad signatures, 400 for malformed payloads and 405 for
+// anything but POST.
+func NewWebhookHandler(opts WebhookOptions) http.Handler {
+	var only providers.ProviderType
+	if opts.Provider != "" {
+		only = resolveProviderType(opts.Provider, "")
+		if only == providers.ProviderGitHubEnterprise {
+			only = providers.ProviderGitHub
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		ev, err := ParseWebhook(r, opts.Secret)
+		switch {
+		case errors.Is(err, ErrInvalidSignature):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, ErrUnsupportedEvent):
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case only != "" && providers.ProviderType(ev.Provider) != only:
+			http.Error(w, fmt.Sprintf("webhook from %s, expected %s", ev.Provider, only), http.StatusBadRequest)
+			return
+		}
+
+		if ev.Action != "" && opts.OnRelease != nil {
+			opts.OnRelease(ev)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
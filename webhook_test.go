@@ -0,0 +1,171 @@
+package gitearelease
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+const webhookSecret = "s3cret"
+
+// newWebhookRequest builds a webhook delivery of the fixture in testdata/webhooks.
+func newWebhookRequest(t *testing.T, fixture string, headers map[string]string) *http.Request {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", fixture))
+	if err != nil {
+		t.Fatalf("Expected fixture %s, got %v", fixture, err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+func sign(t *testing.T, fixture, secret string) string {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", fixture))
+	if err != nil {
+		t.Fatalf("Expected fixture %s, got %v", fixture, err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixture   string
+		headers   map[string]string
+		provider  string
+		action    WebhookAction
+		releaseID int
+		repo      string
+		assetURL  string
+	}{
+		{
+			name:    "gitea",
+			fixture: "gitea_release.json",
+			headers: map[string]string{
+				"X-Gitea-Event":     "release",
+				"X-GitHub-Event":    "release", // Gitea sends GitHub's headers too
+				"X-Gitea-Signature": sign(t, "gitea_release.json", webhookSecret),
+			},
+			provider:  "gitea",
+			action:    WebhookPublished,
+			releaseID: 42,
+			repo:      "earentir/tool",
+			assetURL:  "https://gitea.example.com/earentir/tool/releases/download/v1.4.0/tool_linux_amd64.tar.gz",
+		},
+		{
+			name:    "github",
+			fixture: "github_release.json",
+			headers: map[string]string{
+				"X-GitHub-Event":      "release",
+				"X-Hub-Signature-256": "sha256=" + sign(t, "github_release.json", webhookSecret),
+			},
+			provider:  "github",
+			action:    WebhookPublished,
+			releaseID: 1001,
+			repo:      "earentir/tool",
+			assetURL:  "https://github.com/earentir/tool/releases/download/v1.4.0/tool_linux_amd64.tar.gz",
+		},
+		{
+			name:    "gitlab",
+			fixture: "gitlab_release.json",
+			headers: map[string]string{
+				"X-Gitlab-Event": "Release Hook",
+				"X-Gitlab-Token": webhookSecret,
+			},
+			provider:  "gitlab",
+			action:    WebhookPublished,
+			releaseID: providers.GitLabReleaseID("tools/cli/tool", "v1.4.0"),
+			repo:      "tools/cli/tool",
+			assetURL:  "https://gitlab.example.com/api/v4/projects/321/packages/generic/tool/v1.4.0/tool_linux_amd64.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, err := ParseWebhook(newWebhookRequest(t, tt.fixture, tt.headers), webhookSecret)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if ev.Provider != tt.provider || ev.Action != tt.action {
+				t.Errorf("Expected %s %s, got %s %s", tt.provider, tt.action, ev.Provider, ev.Action)
+			}
+			if ev.Release.TagName != "v1.4.0" || ev.Release.ID != tt.releaseID {
+				t.Errorf("Expected release v1.4.0 with ID %d, got %s with ID %d", tt.releaseID, ev.Release.TagName, ev.Release.ID)
+			}
+			if ev.Release.Body != "## Features\n\n- Webhooks" {
+				t.Errorf("Expected the Markdown body, got %q", ev.Release.Body)
+			}
+			if ev.Repository.FullName != tt.repo {
+				t.Errorf("Expected repository %s, got %s", tt.repo, ev.Repository.FullName)
+			}
+			if len(ev.Release.Assets) != 1 || ev.Release.Assets[0].BrowserDownloadURL != tt.assetURL {
+				t.Errorf("Expected asset %s, got %+v", tt.assetURL, ev.Release.Assets)
+			}
+
+			// A wrong secret is rejected
+			if _, err := ParseWebhook(newWebhookRequest(t, tt.fixture, tt.headers), "wrong"); err != ErrInvalidSignature {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received []WebhookEvent
+	handler := NewWebhookHandler(WebhookOptions{
+		Secret:    webhookSecret,
+		OnRelease: func(ev WebhookEvent) { received = append(received, ev) },
+	})
+
+	githubHeaders := func(event, fixture string) map[string]string {
+		return map[string]string{
+			"X-GitHub-Event":      event,
+			"X-Hub-Signature-256": "sha256=" + sign(t, fixture, webhookSecret),
+		}
+	}
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"release", newWebhookRequest(t, "github_release.json", githubHeaders("release", "github_release.json")), http.StatusNoContent},
+		{"ping", newWebhookRequest(t, "github_ping.json", githubHeaders("ping", "github_ping.json")), http.StatusNoContent},
+		{"unsigned", newWebhookRequest(t, "github_release.json", map[string]string{"X-GitHub-Event": "release"}), http.StatusUnauthorized},
+		{"gitlab token", newWebhookRequest(t, "gitlab_release.json", map[string]string{"X-Gitlab-Event": "Release Hook", "X-Gitlab-Token": "nope"}), http.StatusUnauthorized},
+		{"get", httptest.NewRequest(http.MethodGet, "/hook", nil), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, tt.req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d (%s)", tt.name, tt.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	if len(received) != 1 || received[0].Release.TagName != "v1.4.0" {
+		t.Errorf("Expected one release event, got %+v", received)
+	}
+
+	// Restricting the provider rejects the others
+	giteaOnly := NewWebhookHandler(WebhookOptions{Secret: webhookSecret, Provider: "gitea"})
+	rec := httptest.NewRecorder()
+	giteaOnly.ServeHTTP(rec, newWebhookRequest(t, "github_release.json", githubHeaders("release", "github_release.json")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a GitHub webhook, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
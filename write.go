@@ -0,0 +1,115 @@
+package gitearelease
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/earentir/gitearelease/providers"
+)
+
+// CreateRelease creates a release in the repository selected by r (BaseURL,
+// Provider, Token and User/Repo or Project) and returns it. Specs the provider
+// cannot represent, such as GitLab drafts, fail with an error wrapping
+// errors.ErrUnsupported before any request is made.
+func CreateRelease(r ReleaseToFetch, spec ReleaseSpec) (Release, error) {
+	w, err := newReleaseWriter(r)
+	if err != nil {
+		return Release{}, err
+	}
+
+	apiURL, payload, err := w.writer.CreateReleaseRequest(w.baseURL, w.user, w.repo, providers.ReleaseSpec(spec))
+	if err != nil {
+		return Release{}, err
+	}
+	return w.send(http.MethodPost, apiURL, payload)
+}
+
+// UpdateRelease replaces the metadata of current, as returned by GetReleases,
+// GetReleaseByTag or CreateRelease, with spec and returns the updated release.
+func UpdateRelease(r ReleaseToFetch, current Release, spec ReleaseSpec) (Release, error) {
+	w, err := newReleaseWriter(r)
+	if err != nil {
+		return Release{}, err
+	}
+
+	if spec.TagName == "" {
+		spec.TagName = current.TagName
+	}
+	method, apiURL, payload, err := w.writer.UpdateReleaseRequest(w.baseURL, w.user, w.repo, providerReleaseRef(current), providers.ReleaseSpec(spec))
+	if err != nil {
+		return Release{}, err
+	}
+	return w.send(method, apiURL, payload)
+}
+
+// DeleteRelease deletes current. The git tag is left in place.
+func DeleteRelease(r ReleaseToFetch, current Release) error {
+	w, err := newReleaseWriter(r)
+	if err != nil {
+		return err
+	}
+
+	return w.delete(w.writer.DeleteReleaseURL(w.baseURL, w.user, w.repo, providerReleaseRef(current)))
+}
+
+// releaseWriter holds the resolved provider and repository for a write operation.
+type releaseWriter struct {
+	writer     providers.ReleaseWriter
+	baseURL    string
+	user, repo string
+	headers    http.Header
+}
+
+func newReleaseWriter(r ReleaseToFetch) (*releaseWriter, error) {
+	providerType := resolveProviderType(r.Provider, r.BaseURL)
+	baseURL := normalizeBaseURL(r.BaseURL, providerType)
+	provider := providers.GetProvider(providerType, baseURL)
+
+	writer, ok := provider.(providers.ReleaseWriter)
+	if !ok {
+		return nil, fmt.Errorf("provider %q cannot write releases", providerType)
+	}
+	user, repo := repoCoordinates(r, providerType)
+	return &releaseWriter{
+		writer:  writer,
+		baseURL: baseURL,
+		user:    user,
+		repo:    repo,
+		headers: requestHeaders(provider, r.Token),
+	}, nil
+}
+
+// send issues a JSON write request and converts the release in the response.
+func (w *releaseWriter) send(method, apiURL string, payload interface{}) (Release, error) {
+	data, err := sendJSON(method, apiURL, w.headers, payload)
+	if err != nil {
+		return Release{}, err
+	}
+
+	providerReleases, err := w.writer.NormalizeRelease(data, true)
+	if err != nil {
+		return Release{}, err
+	}
+	if len(providerReleases) == 0 {
+		return Release{}, fmt.Errorf("%s %q: empty response", method, apiURL)
+	}
+	return convertProviderRelease(providerReleases[0]), nil
+}
+
+// delete issues a DELETE request; any 2xx status is a success.
+func (w *releaseWriter) delete(apiURL string) error {
+	resp, err := doRequest(http.MethodDelete, apiURL, w.headers)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{method: http.MethodDelete, url: apiURL, status: resp.Status, code: resp.StatusCode}
+	}
+	return nil
+}
+
+// providerReleaseRef returns the identifying fields of rel that providers need to address it.
+func providerReleaseRef(rel Release) providers.Release {
+	return providers.Release{ID: rel.ID, TagName: rel.TagName, UploadURL: rel.UploadURL}
+}